@@ -0,0 +1,42 @@
+// cmd/alpha.go
+package cmd
+
+import "strings"
+
+// Pixel formats with an alpha channel that don't follow the yuva prefix
+var alphaPixelFormats = []string{"rgba", "bgra", "argb", "abgr", "gbrap", "ya8", "ya16", "pal8"}
+
+// hasAlphaChannel reports whether the probed video stream carries alpha.
+// VP8/VP9 store alpha as side data, so ffprobe reports a plain yuv420p
+// pixel format and an alpha_mode tag instead.
+func hasAlphaChannel(info *VideoInfo) bool {
+	if info.AlphaMode {
+		return true
+	}
+
+	pixFmt := info.PixelFormat
+	if strings.HasPrefix(pixFmt, "yuva") {
+		return true
+	}
+	for _, format := range alphaPixelFormats {
+		if strings.HasPrefix(pixFmt, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// alphaDecoder returns the decoder needed to read the alpha channel of the
+// probed stream, or "" if FFmpeg's default decoder keeps it
+func alphaDecoder(info *VideoInfo) string {
+	if !info.AlphaMode {
+		return ""
+	}
+	switch info.Codec {
+	case "vp9":
+		return "libvpx-vp9"
+	case "vp8":
+		return "libvpx"
+	}
+	return ""
+}
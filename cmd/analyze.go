@@ -0,0 +1,185 @@
+// cmd/analyze.go
+package cmd
+
+import (
+	"fmt"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// gifAnalysis summarizes the structure of a finished GIF
+type gifAnalysis struct {
+	Width, Height int
+	Frames        int
+	GlobalColors  int     // Size of the global color table, 0 if there is none
+	LocalPalettes int     // Frames that carry their own color table
+	ColorsUsed    int     // Distinct opaque colors actually referenced by pixels
+	Transparent   bool    // Some pixels use a transparent palette entry
+	Duration      float64 // Seconds, from the frame delays
+	LoopCount     int     // 0 loops forever, -1 plays once
+	FrameCoverage float64 // Average share of the canvas each frame redraws
+	Comments      []string
+}
+
+// analyzeGIF decodes a GIF and reports its palette and frame structure
+func analyzeGIF(path string) (*gifAnalysis, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s for analysis: %w", path, err)
+	}
+	defer f.Close()
+
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	a := &gifAnalysis{
+		Width:     g.Config.Width,
+		Height:    g.Config.Height,
+		Frames:    len(g.Image),
+		LoopCount: g.LoopCount,
+	}
+
+	global, _ := g.Config.ColorModel.(color.Palette)
+	a.GlobalColors = len(global)
+
+	used := make(map[color.Color]struct{})
+	canvas := float64(a.Width * a.Height)
+	for i, frame := range g.Image {
+		if global == nil || !samePalette(frame.Palette, global) {
+			a.LocalPalettes++
+		}
+
+		// Mark the palette entries this frame uses, then collect their colors
+		var indices [256]bool
+		for _, idx := range frame.Pix {
+			indices[idx] = true
+		}
+		for idx, ok := range indices {
+			if !ok || idx >= len(frame.Palette) {
+				continue
+			}
+			c := frame.Palette[idx]
+			if _, _, _, alpha := c.RGBA(); alpha == 0 {
+				a.Transparent = true
+				continue
+			}
+			used[color.RGBAModel.Convert(c)] = struct{}{}
+		}
+
+		if canvas > 0 {
+			b := frame.Bounds()
+			a.FrameCoverage += float64(b.Dx()*b.Dy()) / canvas
+		}
+		if i < len(g.Delay) {
+			a.Duration += float64(g.Delay[i]) / 100
+		}
+	}
+
+	a.ColorsUsed = len(used)
+	if a.Frames > 0 {
+		a.FrameCoverage /= float64(a.Frames)
+	}
+
+	// The decoder skips comments, so read them from the raw blocks
+	if _, err := f.Seek(0, io.SeekStart); err == nil {
+		a.Comments, _ = gifmaker.ReadGIFComments(f)
+	}
+
+	return a, nil
+}
+
+// samePalette reports whether a frame's palette is the global one. The
+// decoder blanks the frame's transparent entry, so that one is skipped.
+func samePalette(frame, global color.Palette) bool {
+	if len(frame) != len(global) {
+		return false
+	}
+	for i := range frame {
+		if _, _, _, alpha := frame[i].RGBA(); alpha == 0 {
+			continue
+		}
+		if color.RGBAModel.Convert(frame[i]) != color.RGBAModel.Convert(global[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// summaryRows returns the analysis as label and value pairs for the
+// summary box
+func (a *gifAnalysis) summaryRows() [][2]string {
+	palettes := fmt.Sprintf("%d local", a.LocalPalettes)
+	if a.GlobalColors > 0 {
+		palettes = fmt.Sprintf("%d-color global, %d local", a.GlobalColors, a.LocalPalettes)
+	}
+	colors := fmt.Sprintf("%d distinct", a.ColorsUsed)
+	if a.Transparent {
+		colors += " + transparency"
+	}
+
+	rows := [][2]string{
+		{"Colors used:", colors},
+		{"Palettes:", palettes},
+		{"Frame area:", fmt.Sprintf("%.0f%% of canvas on average", a.FrameCoverage*100)},
+		{"Play time:", fmt.Sprintf("%.2f seconds", a.Duration)},
+		{"Loop:", formatLoopCount(a.LoopCount)},
+	}
+	for _, comment := range a.Comments {
+		rows = append(rows, [2]string{"Comment:", truncateComment(comment, 28)})
+	}
+	return rows
+}
+
+// truncateComment shortens a comment to at most width characters so it fits
+// the summary box
+func truncateComment(comment string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(comment), " "))
+	if len(runes) <= width {
+		return string(runes)
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// formatLoopCount describes a GIF's loop count
+func formatLoopCount(n int) string {
+	switch {
+	case n == 0:
+		return "forever"
+	case n < 0:
+		return "plays once"
+	default:
+		return fmt.Sprintf("%d extra times", n)
+	}
+}
+
+// countPaletteColors returns the number of distinct colors in a palette
+// PNG. palettegen fills unused slots with black, which counts once.
+func countPaletteColors(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open palette: %w", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode palette %s: %w", path, err)
+	}
+
+	colors := make(map[color.RGBA]struct{})
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			colors[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)] = struct{}{}
+		}
+	}
+	return len(colors), nil
+}
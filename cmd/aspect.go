@@ -0,0 +1,125 @@
+// cmd/aspect.go
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Common social media aspect ratios offered by --aspect
+var aspectPresets = []string{"1:1", "4:5", "16:9", "9:16"}
+
+// List of supported ways to reach an aspect ratio
+var validAspectModes = []string{"crop", "pad"}
+
+// isValidAspectMode checks if the aspect mode is supported
+func isValidAspectMode(mode string) bool {
+	return slices.Contains(validAspectModes, mode)
+}
+
+// parseAspect parses an aspect ratio like 16:9
+func parseAspect(aspect string) (int, int, error) {
+	w, h, ok := strings.Cut(aspect, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid aspect ratio %q (expected W:H, e.g. %s)", aspect, strings.Join(aspectPresets, ", "))
+	}
+	rw, errW := strconv.Atoi(strings.TrimSpace(w))
+	rh, errH := strconv.Atoi(strings.TrimSpace(h))
+	if errW != nil || errH != nil || rw < 1 || rh < 1 {
+		return 0, 0, fmt.Errorf("invalid aspect ratio %q (expected W:H, e.g. %s)", aspect, strings.Join(aspectPresets, ", "))
+	}
+	return rw, rh, nil
+}
+
+// aspectCrop returns the largest centered W:H:X:Y region of a w x h frame
+// with the given ratio, using even dimensions
+func aspectCrop(w, h, rw, rh int) string {
+	cw, ch := w, h
+	if w*rh > h*rw {
+		// Too wide, keep the full height
+		cw = h * rw / rh
+	} else {
+		// Too tall, keep the full width
+		ch = w * rh / rw
+	}
+	cw, ch = cw&^1, ch&^1
+	return fmt.Sprintf("%d:%d:%d:%d", cw, ch, (w-cw)/2, (h-ch)/2)
+}
+
+// aspectPad returns the smallest W:H:X:Y canvas with the given ratio that
+// fits a w x h frame centered in it, using even dimensions
+func aspectPad(w, h, rw, rh int) string {
+	pw, ph := w, h
+	if w*rh > h*rw {
+		// Too wide, add bars above and below
+		ph = (w*rh + rw - 1) / rw
+	} else {
+		// Too tall, add bars at the sides
+		pw = (h*rw + rh - 1) / rh
+	}
+	pw, ph = (pw+1)&^1, (ph+1)&^1
+	return fmt.Sprintf("%d:%d:%d:%d", pw, ph, (pw-w)/2, (ph-h)/2)
+}
+
+// aspectOutputSize returns even output dimensions with the given ratio at
+// the requested width
+func aspectOutputSize(width, rw, rh int) (int, int) {
+	width &^= 1
+	return width, (width * rh / rw) &^ 1
+}
+
+// parseCropRect parses a W:H:X:Y crop rectangle. The size has to be
+// positive and the offsets can't be negative.
+func parseCropRect(crop string) (w, h, x, y int, err error) {
+	parts := strings.Split(crop, ":")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid crop %q", crop)
+	}
+	values := make([]int, 4)
+	for i, part := range parts {
+		if values[i], err = strconv.Atoi(part); err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid crop %q", crop)
+		}
+	}
+	if values[0] <= 0 || values[1] <= 0 || values[2] < 0 || values[3] < 0 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid crop %q", crop)
+	}
+	return values[0], values[1], values[2], values[3], nil
+}
+
+// applyAspect sets the crop or pad needed to reach opts.Aspect for a source
+// of the given size. Any crop already set by --crop or --autocrop is taken
+// into account.
+func applyAspect(sourceWidth, sourceHeight int) error {
+	rw, rh, err := parseAspect(opts.Aspect)
+	if err != nil {
+		return err
+	}
+
+	// Work from the cropped region if there is one
+	w, h, x, y := sourceWidth, sourceHeight, 0, 0
+	if opts.crop != "" {
+		if w, h, x, y, err = parseCropRect(opts.crop); err != nil {
+			return err
+		}
+	}
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("could not determine the video dimensions for --aspect")
+	}
+
+	if opts.AspectMode == "pad" {
+		opts.pad = aspectPad(w, h, rw, rh)
+	} else {
+		cw, ch, cx, cy, _ := parseCropRect(aspectCrop(w, h, rw, rh))
+		opts.crop = fmt.Sprintf("%d:%d:%d:%d", cw, ch, x+cx, y+cy)
+	}
+
+	// Scale to exact even dimensions so rounding can't skew the ratio
+	if opts.Width > 0 {
+		opts.Width, opts.height = aspectOutputSize(opts.Width, rw, rh)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// cmd/aspect_test.go
+package cmd
+
+import "testing"
+
+func TestParseCropRect(t *testing.T) {
+	tests := []struct {
+		crop       string
+		w, h, x, y int
+		wantErr    bool
+	}{
+		{"640:360:0:60", 640, 360, 0, 60, false},
+		{"1:1:1919:1079", 1, 1, 1919, 1079, false},
+		{"640:360:0", 0, 0, 0, 0, true},
+		{"640:360:0:60:1", 0, 0, 0, 0, true},
+		{"640", 0, 0, 0, 0, true},
+		{"", 0, 0, 0, 0, true},
+		{"-640:360:0:0", 0, 0, 0, 0, true},
+		{"640:-360:0:0", 0, 0, 0, 0, true},
+		{"640:360:-1:0", 0, 0, 0, 0, true},
+		{"640:360:0:-1", 0, 0, 0, 0, true},
+		{"0:360:0:0", 0, 0, 0, 0, true},
+		{"640:0:0:0", 0, 0, 0, 0, true},
+		{"640:abc:0:0", 0, 0, 0, 0, true},
+		{"640:360:0:1.5", 0, 0, 0, 0, true},
+		{"iw:ih:0:0", 0, 0, 0, 0, true},
+		{"640:360:0: 60", 0, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		w, h, x, y, err := parseCropRect(tt.crop)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCropRect(%q) error = %v, wantErr %v", tt.crop, err, tt.wantErr)
+			continue
+		}
+		if w != tt.w || h != tt.h || x != tt.x || y != tt.y {
+			t.Errorf("parseCropRect(%q) = %d:%d:%d:%d, want %d:%d:%d:%d", tt.crop, w, h, x, y, tt.w, tt.h, tt.x, tt.y)
+		}
+	}
+}
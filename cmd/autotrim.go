@@ -0,0 +1,141 @@
+// cmd/autotrim.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A run of frames must stay static this long to be trimmed, and this much
+// of it is kept so the GIF doesn't start or stop mid-motion
+const (
+	autoTrimMinStatic = 1.0
+	autoTrimKeep      = 0.5
+)
+
+// Default share (0-1) by which frames may differ and still count as static.
+// A little above freezedetect's own default, to ignore cursor blinks and
+// encoder noise in screen recordings.
+const defaultAutoTrimThreshold = 0.003
+
+// freezeInterval is a run of static frames, in seconds from the start of
+// the clip. End is -1 if the run lasts until the clip ends.
+type freezeInterval struct {
+	Start, End float64
+}
+
+var freezeDetectRegex = regexp.MustCompile(`lavfi\.freezedetect\.freeze_(start|end): (-?[\d.]+)`)
+
+// detectFreezes runs freezedetect over the clip
+func detectFreezes(ffmpegPath string, start, duration, threshold float64) ([]freezeInterval, error) {
+	logger := GetLogger()
+
+	args := []string{"-hide_banner"}
+	if start > 0 {
+		args = append(args, "-ss", formatSeconds(start))
+	}
+	args = append(args, "-i", opts.Input)
+	if duration > 0 {
+		args = append(args, "-t", formatSeconds(duration))
+	}
+	args = append(args,
+		"-vf", fmt.Sprintf("freezedetect=n=%s:d=%s", strconv.FormatFloat(threshold, 'f', -1, 64), formatSeconds(autoTrimMinStatic)),
+		"-map", "0:v:0",
+		"-f", "null",
+		"-",
+	)
+
+	logger.Debugf("FFmpeg freezedetect command: %s %s", ffmpegPath, strings.Join(args, " "))
+	output, err := exec.Command(ffmpegPath, args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("freezedetect failed: %w", err)
+	}
+	return parseFreezeDetect(string(output)), nil
+}
+
+// parseFreezeDetect reads the static runs from freezedetect's log output
+func parseFreezeDetect(output string) []freezeInterval {
+	var freezes []freezeInterval
+	for _, match := range freezeDetectRegex.FindAllStringSubmatch(output, -1) {
+		t, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		if t < 0 {
+			t = 0
+		}
+		switch match[1] {
+		case "start":
+			freezes = append(freezes, freezeInterval{Start: t, End: -1})
+		case "end":
+			if len(freezes) > 0 && freezes[len(freezes)-1].End < 0 {
+				freezes[len(freezes)-1].End = t
+			}
+		}
+	}
+	return freezes
+}
+
+// autoTrimRange returns how many seconds of static frames to drop from the
+// start and the end of a clip. A clip that is static throughout is kept
+// as it is.
+func autoTrimRange(freezes []freezeInterval, clipDuration float64) (lead, tail float64) {
+	// Allow for the first and last frames not landing exactly on the clip edges
+	const edge = 0.1
+
+	if len(freezes) == 0 {
+		return 0, 0
+	}
+	first, last := freezes[0], freezes[len(freezes)-1]
+	toEnd := func(f freezeInterval) bool {
+		return f.End < 0 || (clipDuration > 0 && f.End >= clipDuration-edge)
+	}
+	if first.Start <= edge && toEnd(first) {
+		return 0, 0
+	}
+
+	if first.Start <= edge {
+		lead = max(0, first.End-autoTrimKeep)
+	}
+	if toEnd(last) && clipDuration > 0 {
+		tail = max(0, clipDuration-(last.Start+autoTrimKeep))
+	}
+	return lead, tail
+}
+
+// autoTrim drops static frames from the start and end of the clip by
+// moving opts.Start and opts.Duration, which replaces opts.End, and reports
+// what it trimmed. If detection fails the clip is kept as it is.
+func autoTrim(ffmpegPath string, totalDuration float64) {
+	logger := GetLogger()
+
+	start, clipDuration := resolveClipRange(totalDuration)
+	freezes, err := detectFreezes(ffmpegPath, start, clipDuration, opts.AutoTrimThreshold)
+	if err != nil {
+		logger.Warnf("Static frame detection failed, not trimming: %v", err)
+		return
+	}
+	for _, f := range freezes {
+		logger.Debugf("Static frames from %.2fs to %.2fs", f.Start, f.End)
+	}
+
+	lead, tail := autoTrimRange(freezes, clipDuration)
+	if lead == 0 && tail == 0 {
+		logger.Info("Auto trim found no static frames at the start or end")
+		fmt.Println("No static frames at the start or end, not trimming")
+		return
+	}
+
+	opts.Start = formatTimestamp(start + lead)
+	trimmed := "from " + opts.Start
+	if clipDuration > 0 {
+		opts.Duration = formatTimestamp(clipDuration - lead - tail)
+		opts.End = ""
+		trimmed = fmt.Sprintf("%s to %s", opts.Start, formatTimestamp(start+clipDuration-tail))
+	}
+	logger.Infof("Auto trim: dropping %.2fs at the start and %.2fs at the end, clip is now %s", lead, tail, trimmed)
+	fmt.Printf("Auto trim: dropping %.1fs of static frames at the start and %.1fs at the end (clip is now %s)\n", lead, tail, trimmed)
+}
@@ -0,0 +1,185 @@
+// cmd/batch.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// batchEntry is one conversion job from an input list
+type batchEntry struct {
+	Line   int
+	Input  string
+	Output string // Empty to use the default output path
+}
+
+// batchResult is the outcome of one batch entry
+type batchResult struct {
+	Entry  batchEntry
+	Output string
+	Err    error
+}
+
+var (
+	batchInputList      string
+	batchOutputTemplate string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Convert every video listed in a file",
+	Long: `Convert every video listed in an input list file, one per line, with the
+same options. A line may name its own output after a tab:
+
+  clips/intro.mp4
+  clips/demo.mp4	gifs/demo-small.gif
+
+Blank lines and lines starting with # are skipped. Outputs that aren't
+named in the list can be named with --output-template, e.g.
+{name}-{width}px-{fps}fps.gif. Accepts all of the
+convert command's options except --input, --output and --interactive.`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := GetLogger()
+
+		if opts.Input != "" || opts.Output != "" || opts.Interactive {
+			return usageErrorf("batch takes its inputs and outputs from --input-list, not --input, --output or --interactive")
+		}
+
+		if batchOutputTemplate != "" {
+			if err := validateOutputTemplate(batchOutputTemplate); err != nil {
+				return asUsageError(err)
+			}
+		}
+
+		f, err := os.Open(expandPath(batchInputList))
+		if err != nil {
+			return fmt.Errorf("failed to open input list: %w", err)
+		}
+		entries, err := parseInputList(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read input list %s: %w", batchInputList, err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("input list %s has no entries", batchInputList)
+		}
+		logger.Infof("Batch converting %d entries from %s", len(entries), batchInputList)
+
+		// Stop the whole batch on Ctrl+C, not just the current conversion
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		cmd.SetContext(ctx)
+
+		// Conversions adjust the options as they go, so each starts from
+		// the ones given on the command line
+		base := opts
+		date := time.Now()
+		usedOutputs := make(map[string]int)
+		var results []batchResult
+		for i, entry := range entries {
+			if ctx.Err() != nil {
+				break
+			}
+			color.New(color.FgHiCyan, color.Bold).Printf("\n[%d/%d] %s\n", i+1, len(entries), entry.Input)
+
+			opts = base
+			opts.Input = entry.Input
+			opts.Output = entry.Output
+			if opts.Output == "" && batchOutputTemplate != "" {
+				opts.Output = expandOutputTemplate(batchOutputTemplate, outputTemplateValues(expandPath(entry.Input), i+1, len(entries), date))
+			}
+
+			// Templates without {index} can give two entries the same name
+			var err error
+			if line, ok := usedOutputs[filepath.Clean(opts.Output)]; ok && opts.Output != "" {
+				err = fmt.Errorf("output %s is already written by line %d (add {index} to the template to tell them apart)", opts.Output, line)
+			} else {
+				if opts.Output != "" {
+					usedOutputs[filepath.Clean(opts.Output)] = entry.Line
+				}
+				err = convertCmd.RunE(cmd, nil)
+			}
+			if err != nil {
+				logger.Errorf("Batch entry on line %d (%s) failed: %v", entry.Line, entry.Input, err)
+				color.Red("❌ %v", err)
+			}
+			results = append(results, batchResult{Entry: entry, Output: opts.Output, Err: err})
+		}
+		opts = base
+
+		return printBatchResults(results, len(entries))
+	},
+}
+
+// parseInputList reads batch entries, one input per line with an optional
+// output after a tab. Blank lines and # comments are skipped.
+func parseInputList(r io.Reader) ([]batchEntry, error) {
+	var entries []batchEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		input, output, _ := strings.Cut(text, "\t")
+		entry := batchEntry{
+			Line:   line,
+			Input:  strings.TrimSpace(input),
+			Output: strings.TrimSpace(output),
+		}
+		if strings.Contains(entry.Output, "\t") {
+			return nil, fmt.Errorf("line %d: expected an input and at most one output separated by a tab", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// printBatchResults lists how each entry went and returns an error if any
+// of them failed or didn't run
+func printBatchResults(results []batchResult, total int) error {
+	failed := 0
+	fmt.Println()
+	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+	for _, r := range results {
+		label := color.New(color.FgHiCyan).Sprintf(" Line %d:", r.Entry.Line)
+		if r.Err != nil {
+			failed++
+			fmt.Printf("│ %-20s %-28s │\n", label, color.RedString("failed: %s", r.Entry.Input))
+			continue
+		}
+		fmt.Printf("│ %-20s %-28s │\n", label, r.Output)
+	}
+	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+
+	skipped := total - len(results)
+	switch {
+	case failed > 0 || skipped > 0:
+		color.New(color.FgHiYellow, color.Bold).Printf("%d of %d converted, %d failed, %d not run\n", len(results)-failed, total, failed, skipped)
+		return fmt.Errorf("batch finished with %d failed and %d skipped entries", failed, skipped)
+	default:
+		color.New(color.FgHiGreen, color.Bold).Printf("✅ All %d entries converted\n", total)
+		return nil
+	}
+}
+
+func init() {
+	batchCmd.Flags().StringVar(&batchInputList, "input-list", "", "File listing the videos to convert, one per line (optionally followed by a tab and an output path)")
+	batchCmd.MarkFlagRequired("input-list")
+	batchCmd.Flags().StringVar(&batchOutputTemplate, "output-template", "", "Name outputs not given in the list, e.g. {name}-{width}px-{fps}fps.gif (placeholders: {name}, {ext}, {width}, {height}, {fps}, {index}, {date})")
+
+	rootCmd.AddCommand(batchCmd)
+}
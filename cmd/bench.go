@@ -0,0 +1,184 @@
+// cmd/bench.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+type BenchOptions struct {
+	Repeat   int
+	FPS      int
+	Width    int
+	Threads  int
+	Start    string
+	Duration string
+}
+
+var benchOpts BenchOptions
+
+// benchRun is the measurement of a single benchmark conversion
+type benchRun struct {
+	Wall        time.Duration
+	Frames      int
+	OutputBytes int64
+	Realtime    float64 // FFmpeg's average processing rate relative to real-time
+}
+
+// framesPerSecond returns how many GIF frames were produced per second
+func (r benchRun) framesPerSecond() float64 {
+	if r.Wall <= 0 {
+		return 0
+	}
+	return float64(r.Frames) / r.Wall.Seconds()
+}
+
+// outputMBPerSecond returns how many megabytes of GIF were written per second
+func (r benchRun) outputMBPerSecond() float64 {
+	if r.Wall <= 0 {
+		return 0
+	}
+	return float64(r.OutputBytes) / 1024 / 1024 / r.Wall.Seconds()
+}
+
+// formatBenchRun formats a run as a stable, space-separated key=value line
+func formatBenchRun(label string, r benchRun) string {
+	return fmt.Sprintf("%s wall_s=%.3f frames=%d fps=%.2f output_mb=%.3f output_mb_per_s=%.3f realtime=%.2fx",
+		label, r.Wall.Seconds(), r.Frames, r.framesPerSecond(), float64(r.OutputBytes)/1024/1024, r.outputMBPerSecond(), r.Realtime)
+}
+
+// averageBenchRuns averages the measurements of several runs
+func averageBenchRuns(runs []benchRun) benchRun {
+	var avg benchRun
+	if len(runs) == 0 {
+		return avg
+	}
+	for _, r := range runs {
+		avg.Wall += r.Wall
+		avg.Frames += r.Frames
+		avg.OutputBytes += r.OutputBytes
+		avg.Realtime += r.Realtime
+	}
+	n := len(runs)
+	avg.Wall /= time.Duration(n)
+	avg.Frames /= n
+	avg.OutputBytes /= int64(n)
+	avg.Realtime /= float64(n)
+	return avg
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [video file]",
+	Short: "Measure conversion speed on this machine",
+	Long: `Convert a video to GIF one or more times, discarding the output, and report
+the wall time, frames per second, output megabytes per second and speed
+relative to real-time. Use it to compare machines or to tune --threads and
+quality against speed.
+
+Every run prints one line of space-separated key=value pairs, followed by an
+average line when --repeat is above 1.`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		videoPath := args[0]
+
+		// Validate input file exists
+		if _, err := os.Stat(videoPath); os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "input file", Path: videoPath}
+		}
+
+		if benchOpts.Repeat < 1 {
+			return usageErrorf("repeat count must be at least 1 (got %d)", benchOpts.Repeat)
+		}
+		if benchOpts.FPS < 1 {
+			return usageErrorf("invalid FPS value: %d", benchOpts.FPS)
+		}
+		if benchOpts.Threads < 0 {
+			return usageErrorf("thread count cannot be negative (got %d)", benchOpts.Threads)
+		}
+		if !cmd.Flags().Changed("threads") {
+			benchOpts.Threads = GetOptimalThreads()
+		}
+		for _, t := range []struct{ name, value string }{
+			{"start", benchOpts.Start},
+			{"duration", benchOpts.Duration},
+		} {
+			if _, err := gifmaker.TimeToSeconds(t.value); err != nil {
+				return usageErrorf("--%s: %w (expected HH:MM:SS, MM:SS or seconds)", t.name, err)
+			}
+		}
+
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		tempDir, err := os.MkdirTemp(tempRoot, "gif-maker-bench")
+		if err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		defer removeTemp(tempDir)
+
+		convOpts := gifmaker.Options{
+			FFmpegPath: ffmpegPath,
+			Input:      videoPath,
+			Output:     filepath.Join(tempDir, "bench.gif"),
+			FPS:        benchOpts.FPS,
+			Start:      benchOpts.Start,
+			Duration:   benchOpts.Duration,
+			Width:      benchOpts.Width,
+			Threads:    benchOpts.Threads,
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("input=%s fps=%d width=%d threads=%d repeat=%d\n",
+			videoPath, benchOpts.FPS, benchOpts.Width, benchOpts.Threads, benchOpts.Repeat)
+
+		runs := make([]benchRun, 0, benchOpts.Repeat)
+		for i := 1; i <= benchOpts.Repeat; i++ {
+			startTime := time.Now()
+			result, err := gifmaker.Convert(ctx, convOpts, nil)
+			if err != nil {
+				return fmt.Errorf("benchmark run %d failed: %w", i, err)
+			}
+			run := benchRun{
+				Wall:     time.Since(startTime),
+				Frames:   result.Frames,
+				Realtime: result.AvgProcessRate,
+			}
+			if info, err := os.Stat(convOpts.Output); err == nil {
+				run.OutputBytes = info.Size()
+			}
+			os.Remove(convOpts.Output)
+
+			runs = append(runs, run)
+			fmt.Println(formatBenchRun(fmt.Sprintf("run=%d", i), run))
+		}
+
+		if len(runs) > 1 {
+			fmt.Println(formatBenchRun("run=avg", averageBenchRuns(runs)))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchOpts.Repeat, "repeat", "n", 1, "Number of conversions to run and average")
+	benchCmd.Flags().IntVarP(&benchOpts.FPS, "fps", "f", 10, "Frames per second")
+	benchCmd.Flags().IntVarP(&benchOpts.Width, "width", "w", 0, "Output width in pixels (default: same as input)")
+	benchCmd.Flags().IntVar(&benchOpts.Threads, "threads", 0, "FFmpeg threads (default: number of CPU cores minus 2; 0 lets FFmpeg decide)")
+	benchCmd.Flags().StringVar(&benchOpts.Start, "start", "", "Start time (format: 00:00:00)")
+	benchCmd.Flags().StringVar(&benchOpts.Duration, "duration", "", "Duration (format: 00:00:00)")
+
+	rootCmd.AddCommand(benchCmd)
+}
@@ -0,0 +1,102 @@
+// cmd/chapters.go
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Chapter is a chapter marker read from the input's container
+type Chapter struct {
+	Title string
+	Start float64
+	End   float64
+}
+
+// ffprobeChapters mirrors the part of `ffprobe -show_chapters` JSON we use
+type ffprobeChapters struct {
+	Chapters []struct {
+		StartTime string            `json:"start_time"`
+		EndTime   string            `json:"end_time"`
+		Tags      map[string]string `json:"tags"`
+	} `json:"chapters"`
+}
+
+// GetChapters returns the chapter markers of a video, which may be empty
+func GetChapters(videoPath string) ([]Chapter, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-show_chapters",
+		"-print_format", "json",
+		videoPath)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chapters: %w", err)
+	}
+
+	return parseChapters(output)
+}
+
+// parseChapters parses ffprobe's chapter JSON
+func parseChapters(data []byte) ([]Chapter, error) {
+	var probe ffprobeChapters
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse chapters: %w", err)
+	}
+
+	chapters := make([]Chapter, 0, len(probe.Chapters))
+	for i, c := range probe.Chapters {
+		start, err1 := strconv.ParseFloat(c.StartTime, 64)
+		end, err2 := strconv.ParseFloat(c.EndTime, 64)
+		if err1 != nil || err2 != nil || end <= start {
+			continue
+		}
+
+		title := strings.TrimSpace(c.Tags["title"])
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		chapters = append(chapters, Chapter{Title: title, Start: start, End: end})
+	}
+
+	return chapters, nil
+}
+
+// unsafeFileNameChars matches runs of characters that don't belong in a filename
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeFileName turns a chapter title into a safe filename component
+func sanitizeFileName(title string) string {
+	name := unsafeFileNameChars.ReplaceAllString(title, "-")
+	name = strings.Trim(name, "-.")
+	if len(name) > 64 {
+		name = strings.TrimRight(name[:64], "-.")
+	}
+	if name == "" {
+		return "chapter"
+	}
+	return name
+}
+
+// chapterSegments turns chapters into segments named after their titles,
+// e.g. out.gif becomes out-01-Introduction.gif for the first chapter
+func chapterSegments(chapters []Chapter, output string) []segment {
+	ext := filepath.Ext(output)
+	stem := strings.TrimSuffix(output, ext)
+
+	segments := make([]segment, len(chapters))
+	for i, c := range chapters {
+		segments[i] = segment{
+			Start:    c.Start,
+			Duration: c.End - c.Start,
+			Output:   fmt.Sprintf("%s-%02d-%s%s", stem, i+1, sanitizeFileName(c.Title), ext),
+		}
+	}
+	return segments
+}
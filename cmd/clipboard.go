@@ -0,0 +1,52 @@
+// cmd/clipboard.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// copyToClipboard puts text on the system clipboard using the platform's
+// clipboard tool
+func copyToClipboard(text string) error {
+	var candidates [][]string
+
+	// Determine the clipboard command based on OS
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip.exe"}}
+	case "linux":
+		// X11 tools first, then Wayland
+		candidates = [][]string{
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			{"wl-copy"},
+		}
+	default:
+		return fmt.Errorf("clipboard is not supported on %s", runtime.GOOS)
+	}
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %w: %s", candidate[0], err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	names := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		names[i] = candidate[0]
+	}
+	return fmt.Errorf("no clipboard tool found (tried %s)", strings.Join(names, ", "))
+}
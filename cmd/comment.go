@@ -0,0 +1,24 @@
+// cmd/comment.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// addOutputComments writes --comment into each GIF the conversion wrote.
+// It runs last, since re-encoding a GIF (as --optimize-go does) drops its
+// comments. WebM and MP4 outputs get the comment from FFmpeg instead.
+func addOutputComments(paths []string) error {
+	if opts.Comment == "" || opts.Format != gifmaker.FormatGIF {
+		return nil
+	}
+	for _, path := range paths {
+		if err := gifmaker.AddGIFComment(path, opts.Comment); err != nil {
+			return &conversionError{fmt.Errorf("failed to add the comment to %s: %w", path, err)}
+		}
+		GetLogger().Debugf("Added a %d byte comment to %s", len(opts.Comment), path)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// cmd/concat.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+type ConcatOptions struct {
+	Output string
+	FPS    int
+	Width  int
+}
+
+var concatOpts ConcatOptions
+
+var concatCmd = &cobra.Command{
+	Use:   "concat [video files...]",
+	Short: "Join several clips into a single GIF",
+	Long: `Join several video clips, in order, into a single GIF.
+Clips may have different resolutions: every clip is scaled (and padded if the
+aspect ratio differs) to a common size before they are joined, and one palette
+is generated for the combined result.`,
+	Args: usageArgs(cobra.MinimumNArgs(2)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := GetLogger()
+
+		for _, input := range args {
+			if _, err := os.Stat(input); os.IsNotExist(err) {
+				return &inputNotFoundError{Kind: "input file", Path: input}
+			}
+			if err := checkVideoInput(input); err != nil {
+				return err
+			}
+		}
+
+		if concatOpts.FPS < 1 {
+			return usageErrorf("invalid FPS value: %d", concatOpts.FPS)
+		}
+		if concatOpts.Width < 0 {
+			return usageErrorf("invalid width value: %d", concatOpts.Width)
+		}
+		if concatOpts.Output == "" {
+			concatOpts.Output = defaultOutputPath(args[0])
+		}
+
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		// Probe every clip for its length and the first one for the output size
+		var totalDuration float64
+		var firstDimensions [2]int
+		for i, input := range args {
+			duration, dimensions, err := getVideoMetadata(input, ffmpegPath)
+			if err != nil {
+				logger.Warnf("Could not get metadata for %s: %v", input, err)
+			}
+			if i == 0 {
+				firstDimensions = dimensions
+			}
+			totalDuration += duration
+		}
+
+		width, height := concatOutputSize(concatOpts.Width, firstDimensions[0], firstDimensions[1])
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("could not determine the dimensions of %s", args[0])
+		}
+
+		ffmpegArgs := []string{
+			"-y",
+			"-loglevel", "error",
+			"-threads", fmt.Sprintf("%d", GetOptimalThreads()),
+		}
+		for _, input := range args {
+			ffmpegArgs = append(ffmpegArgs, "-i", input)
+		}
+		ffmpegArgs = append(ffmpegArgs,
+			"-filter_complex", buildConcatFilter(len(args), concatOpts.FPS, width, height),
+			concatOpts.Output,
+		)
+
+		logger.Debugf("FFmpeg command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
+		fmt.Printf("Joining %d clips (%s total) into a %dx%d GIF...\n", len(args), formatDuration(totalDuration), width, height)
+
+		startTime := time.Now()
+		if output, err := exec.Command(ffmpegPath, ffmpegArgs...).CombinedOutput(); err != nil {
+			return &conversionError{fmt.Errorf("FFmpeg concat failed: %w\nError output: %s", err, strings.TrimSpace(string(output)))}
+		}
+		elapsedTime := time.Since(startTime).Seconds()
+
+		fileInfo, err := os.Stat(concatOpts.Output)
+		if err != nil {
+			return fmt.Errorf("failed to get output file info: %w", err)
+		}
+
+		fmt.Println()
+		color.New(color.FgHiGreen, color.Bold).Println("✅ Clips joined successfully!")
+
+		fmt.Println()
+		fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), concatOpts.Output)
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Size:"), HumanizeBytes(fileInfo.Size()))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Clips:"), fmt.Sprintf("%d", len(args)))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Duration:"), fmt.Sprintf("%.2f seconds", totalDuration))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Dimensions:"), fmt.Sprintf("%dx%d", width, height))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Conversion time:"), fmt.Sprintf("%.1f seconds", elapsedTime))
+		fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+
+		logger.Infof("Concat completed: %s (%s) from %d clips in %.1f seconds",
+			concatOpts.Output, HumanizeBytes(fileInfo.Size()), len(args), elapsedTime)
+
+		return nil
+	},
+}
+
+func init() {
+	concatCmd.Flags().StringVarP(&concatOpts.Output, "output", "o", "", "Output GIF file (default: first_input_name.gif)")
+	concatCmd.Flags().IntVarP(&concatOpts.FPS, "fps", "f", 10, "Frames per second")
+	concatCmd.Flags().IntVarP(&concatOpts.Width, "width", "w", 0, "Output width in pixels (default: width of the first clip)")
+
+	rootCmd.AddCommand(concatCmd)
+}
+
+// concatOutputSize returns the common frame size for joined clips: the
+// requested width (or the first clip's) with the first clip's aspect ratio,
+// rounded to even numbers
+func concatOutputSize(width, firstWidth, firstHeight int) (int, int) {
+	if firstWidth <= 0 || firstHeight <= 0 {
+		return 0, 0
+	}
+	if width <= 0 {
+		width = firstWidth
+	}
+	height := firstHeight * width / firstWidth
+	return width &^ 1, height &^ 1
+}
+
+// buildConcatFilter scales every input to the same size, joins them with the
+// concat filter and runs palette generation on the combined stream
+func buildConcatFilter(inputs, fps, width, height int) string {
+	palette := gifmaker.Options{}
+
+	var b strings.Builder
+	for i := 0; i < inputs; i++ {
+		fmt.Fprintf(&b, "[%d:v]fps=%d,scale=%d:%d:force_original_aspect_ratio=decrease:flags=lanczos,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1[v%d];",
+			i, fps, width, height, width, height, i)
+	}
+	for i := 0; i < inputs; i++ {
+		fmt.Fprintf(&b, "[v%d]", i)
+	}
+	fmt.Fprintf(&b, "concat=n=%d:v=1:a=0,split[s0][s1];[s0]%s[p];[s1][p]%s", inputs, palette.PaletteGenFilter(), palette.PaletteUseFilter())
+	return b.String()
+}
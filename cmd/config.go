@@ -0,0 +1,206 @@
+// cmd/config.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the project config file, looked for in the working directory and
+// its parents up to the repository root
+const projectConfigName = ".gif-maker.yaml"
+
+// configSource is a config file and the flags it set
+type configSource struct {
+	Path  string
+	Flags []string
+}
+
+// Flags that were set from a config file rather than on the command line,
+// and the file each one came from
+var configFlags = map[string]string{}
+
+// userConfigPath returns the location of the user's own config file
+func userConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find config directory: %w", err)
+	}
+	return filepath.Join(configDir, "gif-maker", "config.yaml"), nil
+}
+
+// projectConfigPaths returns the project config files that apply in the
+// working directory, nearest first. Inside a repository every directory up
+// to its root is searched; outside one only the working directory is, so
+// a stray file in a home directory doesn't apply everywhere below it.
+func projectConfigPaths() []string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		if isRepoRoot(dir) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			dirs = dirs[:1]
+			break
+		}
+		dir = parent
+	}
+
+	var paths []string
+	for _, d := range dirs {
+		path := filepath.Join(d, projectConfigName)
+		if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// isRepoRoot reports whether dir is the top of a git repository. .git is
+// a file rather than a directory in worktrees and submodules.
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// applyConfigFiles sets the flags of cmd that weren't given on the command
+// line from the project config files and then the user config, so the
+// nearest file wins. It returns the files that set anything, from the
+// lowest precedence to the highest, and warnings about keys that aren't
+// flags of any command.
+func applyConfigFiles(cmd *cobra.Command) ([]configSource, []string, error) {
+	paths := projectConfigPaths()
+	if userPath, err := userConfigPath(); err == nil {
+		if _, err := os.Stat(userPath); err == nil {
+			paths = append(paths, userPath)
+		}
+	}
+
+	var sources []configSource
+	var warnings []string
+	for _, path := range paths {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		source := configSource{Path: path}
+		for _, name := range sortedKeys(values) {
+			f := cmd.Flags().Lookup(name)
+			if f == nil {
+				// Options for other commands can share the file
+				if !isKnownFlag(cmd.Root(), name) {
+					warnings = append(warnings, fmt.Sprintf("Unknown option %q in %s", name, path))
+				}
+				continue
+			}
+			if f.Changed {
+				continue
+			}
+			if err := cmd.Flags().Set(name, values[name]); err != nil {
+				return nil, nil, usageErrorf("invalid %s in %s: %v", name, path, err)
+			}
+			configFlags[name] = path
+			source.Flags = append(source.Flags, name)
+		}
+		if len(source.Flags) > 0 {
+			sources = append([]configSource{source}, sources...)
+		}
+	}
+	return sources, warnings, nil
+}
+
+// readConfigFile parses a config file into flag values. Keys are flag
+// names; lists such as sizes become comma-separated values.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, usageErrorf("invalid config file %s: %v", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			return nil, usageErrorf("%s in %s has no value", name, path)
+		case map[string]any:
+			return nil, usageErrorf("%s in %s must be a value or a list, not a mapping", name, path)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// isKnownFlag reports whether name is a flag of cmd or any of its
+// subcommands
+func isKnownFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, sub := range cmd.Commands() {
+		if isKnownFlag(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportConfigSources logs the config files that set flags, and prints
+// them with --verbose
+func reportConfigSources(sources []configSource, warnings []string) {
+	logger := GetLogger()
+	for _, warning := range warnings {
+		color.Yellow("⚠️ %s", warning)
+		logger.Warn(warning)
+	}
+	for _, source := range sources {
+		logger.Debugf("Config file %s set: %s", source.Path, strings.Join(source.Flags, ", "))
+		if verbose {
+			fmt.Printf("Using config %s (%s)\n", source.Path, strings.Join(source.Flags, ", "))
+		}
+	}
+}
+
+// commandLineFlagCount returns how many flags were given on the command
+// line, leaving out the ones set from config files
+func commandLineFlagCount(flags *pflag.FlagSet) int {
+	return flags.NFlag() - len(configFlags)
+}
+
+// sortedKeys returns the keys of a map in order, so config files are
+// applied and reported the same way every run
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,169 @@
+// cmd/contactsheet.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// contactSheetInterval returns the number of seconds between sampled frames
+// so that rows*cols frames are spread evenly across the clip
+func contactSheetInterval(clipDuration float64, rows, cols int) float64 {
+	tiles := rows * cols
+	if clipDuration <= 0 || tiles <= 0 {
+		return 0
+	}
+	return clipDuration / float64(tiles)
+}
+
+// contactSheetSeek returns the -ss and -t to put before the input, and how
+// far into what they select the clip starts. Like convert, the seek is
+// exact by default, on the input side with --fast-seek and split in two
+// with --seek-accurate. The exact part is left to the select filter: an
+// -ss after the input only trims frames once they have been tiled.
+func contactSheetSeek(start, clipDuration float64, limit bool) ([]string, float64) {
+	inputStart := 0.0
+	switch {
+	case opts.FastSeek:
+		inputStart = start
+	case opts.SeekAccurate:
+		inputStart, _ = gifmaker.SplitSeek(start)
+	}
+	offset := start - inputStart
+
+	var args []string
+	if inputStart > 0 {
+		args = append(args, "-ss", formatSeconds(inputStart))
+	}
+	if limit {
+		args = append(args, "-t", formatSeconds(offset+clipDuration))
+	}
+	return args, offset
+}
+
+// createContactSheet renders a single PNG grid of evenly-spaced frames
+func createContactSheet(ctx context.Context, ffmpegPath string) (*ConversionResult, error) {
+	logger := GetLogger()
+
+	// Work out how much of the video we are sampling from
+	totalDuration, _, err := getVideoMetadata(opts.Input, ffmpegPath)
+	if err != nil {
+		logger.Warnf("Could not get video metadata: %v", err)
+	}
+
+	start, clipDuration := resolveClipRange(totalDuration)
+	if clipDuration <= 0 {
+		return nil, fmt.Errorf("could not determine video duration for contact sheet: %s", opts.Input)
+	}
+
+	interval := contactSheetInterval(clipDuration, opts.Rows, opts.Cols)
+	logger.Debugf("Contact sheet: %dx%d tiles, one frame every %.3f seconds", opts.Cols, opts.Rows, interval)
+
+	ffmpegArgs := []string{
+		"-y",
+		"-loglevel", "error",
+	}
+	if opts.Threads > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-threads", fmt.Sprintf("%d", opts.Threads))
+	}
+
+	seekArgs, offset := contactSheetSeek(start, clipDuration, clipDurationOption() != "")
+	ffmpegArgs = append(ffmpegArgs, seekArgs...)
+	ffmpegArgs = append(ffmpegArgs, libraryOptions(ffmpegPath, "").InputArgs()...)
+	ffmpegArgs = append(ffmpegArgs, "-i", opts.Input)
+
+	// Select the first frame and then one frame per interval
+	filter := fmt.Sprintf("select='isnan(prev_selected_t)+gte(t-prev_selected_t,%.3f)'", interval)
+	if offset > 0 {
+		filter = fmt.Sprintf("select='gte(t,%.3f)*(isnan(prev_selected_t)+gte(t-prev_selected_t,%.3f))'", offset, interval)
+	}
+
+	// Width applies to the whole sheet, so scale each tile to its share
+	if opts.Width > 0 {
+		tileWidth := opts.Width / opts.Cols
+		if tileWidth < 1 {
+			tileWidth = 1
+		}
+		filter = fmt.Sprintf("%s,scale=%d:-1:flags=lanczos", filter, tileWidth)
+	}
+
+	filter = fmt.Sprintf("%s,tile=%dx%d", filter, opts.Cols, opts.Rows)
+
+	ffmpegArgs = append(ffmpegArgs,
+		"-vf", filter,
+		"-frames:v", "1",
+		"-fps_mode", "vfr",
+		opts.Output,
+	)
+
+	if opts.DumpCommand != "" {
+		if err := dumpCommand(opts.DumpCommand, ffmpegPath, ffmpegArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Contact sheet: %d cols x %d rows, one frame every %.2f seconds\n", opts.Cols, opts.Rows, interval)
+		fmt.Println(formatShellCommand(ffmpegPath, ffmpegArgs))
+		color.Green("Dry run, nothing written.")
+		return nil, nil
+	}
+
+	logger.Debugf("FFmpeg command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	if rootCmd.Flag("verbose").Value.String() == "true" {
+		fmt.Printf("Running FFmpeg command: %s %s\n", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	}
+
+	fmt.Printf("Creating %dx%d contact sheet...\n", opts.Cols, opts.Rows)
+
+	startTime := time.Now()
+	output, err := runFFmpeg(ctx, ffmpegPath, ffmpegArgs...)
+	if err != nil {
+		if ctx.Err() != nil {
+			os.Remove(opts.Output)
+			return nil, ctx.Err()
+		}
+		return nil, &conversionError{fmt.Errorf("FFmpeg contact sheet failed: %w\nError output: %s", err, strings.TrimSpace(string(output)))}
+	}
+	elapsed := time.Since(startTime)
+
+	sheet, err := statOutput(opts.Output, "")
+	if err != nil {
+		return nil, err
+	}
+	return &ConversionResult{
+		Outputs:       []ConversionOutput{sheet},
+		Frames:        opts.Rows * opts.Cols,
+		Elapsed:       elapsed,
+		FrameInterval: interval,
+	}, nil
+}
+
+// printContactSheetSummary prints the summary box for a contact sheet
+func printContactSheetSummary(result *ConversionResult) {
+	logger := GetLogger()
+	sheet := result.Outputs[0]
+	elapsedTime := result.Elapsed.Seconds()
+
+	fmt.Println()
+	color.New(color.FgHiGreen, color.Bold).Println("✅ Contact sheet created successfully!")
+
+	fmt.Println()
+	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), sheet.Path)
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Size:"), HumanizeBytes(sheet.Size))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Grid:"), fmt.Sprintf("%d cols x %d rows", opts.Cols, opts.Rows))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Frame interval:"), fmt.Sprintf("%.2f seconds", result.FrameInterval))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Conversion time:"), fmt.Sprintf("%.1f seconds", elapsedTime))
+	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+
+	logger.Infof("Contact sheet completed: %s (%s) in %.1f seconds",
+		sheet.Path, HumanizeBytes(sheet.Size), elapsedTime)
+}
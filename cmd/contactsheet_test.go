@@ -0,0 +1,37 @@
+// cmd/contactsheet_test.go
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContactSheetSeek(t *testing.T) {
+	saved := opts
+	t.Cleanup(func() { opts = saved })
+
+	tests := []struct {
+		name           string
+		fast, accurate bool
+		start          float64
+		limit          bool
+		args           []string
+		offset         float64
+	}{
+		{"no start", false, false, 0, false, nil, 0},
+		{"exact", false, false, 90.5, false, nil, 90.5},
+		{"exact with a duration", false, false, 90.5, true, []string{"-t", "100.500"}, 90.5},
+		{"fast", true, false, 90.5, true, []string{"-ss", "90.500", "-t", "10.000"}, 0},
+		{"accurate", false, true, 90.5, true, []string{"-ss", "89.000", "-t", "11.500"}, 1.5},
+		{"accurate near the start", false, true, 1.5, false, nil, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts.FastSeek, opts.SeekAccurate = tt.fast, tt.accurate
+			args, offset := contactSheetSeek(tt.start, 10, tt.limit)
+			if !slices.Equal(args, tt.args) || offset != tt.offset {
+				t.Errorf("contactSheetSeek(%g) = %v, %g, want %v, %g", tt.start, args, offset, tt.args, tt.offset)
+			}
+		})
+	}
+}
@@ -2,17 +2,21 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"image/png"
 	"math"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -22,18 +26,193 @@ import (
 	"github.com/vbauerster/mpb/v7/decor"
 
 	"github.com/Akashdeep-Patra/gif-maker/internal/ffmpeg"
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
 )
 
 type ConvertOptions struct {
 	Input       string
 	Output      string
 	FPS         int
+	FPSMode     string
+	fpsArg      string // Raw --fps value, a number or "auto"
+	fpsAuto     bool   // Match the source frame rate
+	MinFPS      int    // Clamp the resolved frame rate, 0 for no limit
+	MaxFPS      int
+	fpsClamped  int     // Frame rate before clamping, 0 if it wasn't clamped
+	Interpolate string  // Make up new frames with minterpolate (blend, mci) instead of repeating them
+	sourceFPS   float64 // Source frame rate, for reporting interpolation
+	Delay       int
 	Start       string
 	Duration    string
+	End         string
+	FastSeek    bool
 	Width       int
+	Height      int
+	Fit         string // How to fit a --width x --height box (contain, cover, stretch)
+
+	// Round the output size to even numbers; MP4 output always is
+	ForceEvenDimensions bool
+
+	// Seek to --start quickly on the input side, then exactly on the
+	// output side for the last second or two
+	SeekAccurate bool
+
 	Quality     int
+	Format      string
 	Interactive bool
 	NoProgress  bool
+	NoOverwrite bool
+	HWAccel     string
+	Threads     int           // FFmpeg threads, 0 lets FFmpeg decide
+	Nice        int           // Niceness to run FFmpeg at on Unix, 0 keeps the default
+	Timeout     time.Duration // Give up on a conversion that runs longer, 0 for no limit
+	Parallel    int
+	Resume      bool // Keep --parallel intermediates in the cache to resume later
+	PaletteFile string
+
+	// AutoTrim drops static frames from the start and end of the clip
+	AutoTrim          bool
+	AutoTrimThreshold float64
+
+	// AutoStatsMode picks palettegen's stats mode from a sampling pass
+	AutoStatsMode bool
+	statsMode     string
+
+	// Fine-tuning of paletteuse. DitherScale only applies if given, since
+	// 0 is a valid bayer_scale.
+	DitherScale    int
+	DiffMode       string
+	NewPalette     bool
+	AlphaThreshold int
+	dither         string
+
+	// Force the demuxer for raw streams FFmpeg can't detect
+	InputFormat    string
+	InputFrameRate string
+
+	// Sample mode builds a slideshow from evenly-spaced frames
+	SampleFrames   int
+	FrameHold      float64
+	sampleInterval int // Source frames between samples, resolved from the probe
+
+	// SceneThreshold builds a highlight GIF from scene changes only
+	SceneThreshold float64
+
+	// Dedupe drops near-duplicate frames with mpdecimate
+	Dedupe          bool
+	DedupeThreshold float64
+
+	// SpeedCurve is time:factor keyframes for the playback speed
+	SpeedCurve string
+	speedCurve []gifmaker.SpeedKeyframe
+
+	// Ranges are start-end pairs of the video joined into one clip
+	Ranges     []string
+	timeRanges []gifmaker.TimeRange
+
+	// Subtitles burns in captions from an external subtitle file
+	Subtitles     string
+	SubtitleStyle string
+
+	// Sizes produces one GIF per width from a single decode
+	Sizes []int
+
+	// Forget clears the remembered interactive-mode history
+	Forget bool
+
+	// Rotate and flip fix the orientation of sideways phone videos
+	Rotate       int
+	Flip         string
+	NoAutorotate bool // Ignore the source's rotation metadata
+	autoRotate   int  // Clockwise rotation from the source's metadata
+	rotateProbed bool // autoRotate was read from the source
+
+	// Autocrop detects and removes black bars before converting
+	Autocrop bool
+	Crop     string // Manual W:H:X:Y crop rectangle
+	crop     string // Resolved W:H:X:Y crop rectangle
+
+	// ColorRange overrides the source's color range (tv, pc) and Tonemap
+	// converts HDR to SDR with the given operator
+	ColorRange string
+	Tonemap    string
+
+	// Clean-up filters for noisy sources (light, medium, strong)
+	Denoise string
+	Deband  string
+
+	// Sharpen counteracts the softness of downscaling (0-10)
+	Sharpen int
+
+	// Aspect crops or pads to a ratio such as 1:1 or 9:16
+	Aspect     string
+	AspectMode string
+	pad        string // Resolved W:H:X:Y pad canvas
+	height     int    // Output height matching the aspect ratio
+
+	// Hold the first and last frame for this many seconds
+	StartPause float64
+	EndPause   float64
+
+	// Waveform overlays the audio as waves or a spectrum, in a band
+	// WaveformHeight percent of the frame tall at WaveformPosition
+	Waveform         string
+	WaveformPosition string
+	WaveformHeight   int
+	WaveformColor    string
+
+	// PreserveAlpha keeps (one-bit) transparency from sources with alpha
+	PreserveAlpha bool
+	decoder       string // Input decoder needed to read the alpha channel
+
+	// DownloadTimeout limits how long downloading a URL input may take
+	DownloadTimeout time.Duration
+
+	// FFmpegVerbose streams FFmpeg's own log instead of the progress bar
+	FFmpegVerbose bool
+
+	// ProgressFormat is "text" for the progress bar or "json" for events
+	// on stderr
+	ProgressFormat string
+
+	// DumpCommand writes the FFmpeg invocation to a file ("-" for stdout)
+	DumpCommand string
+	DryRun      bool
+
+	// Segment splits the clip into consecutive GIFs of this many seconds
+	Segment    float64
+	ByChapters bool // One GIF per chapter marker instead
+
+	// Upload names the provider to publish the finished GIF to
+	Upload string
+
+	// Clipboard copies the output path (or uploaded URL) when done
+	Clipboard bool
+
+	// Analyze decodes the finished GIF and reports its palette and frames
+	Analyze bool
+
+	// OptimizeGo merges duplicate frames of the finished GIF in pure Go
+	OptimizeGo bool
+
+	// StripMetadata leaves the source's metadata out; Comment embeds an
+	// attribution or note in the output
+	StripMetadata bool
+	Comment       string
+
+	// ForceReencode sends GIF inputs through the full palette pipeline
+	// instead of resizing and trimming their frames as they are
+	ForceReencode bool
+
+	// Web is shorthand for --format mp4, an H.264 clip for autoplaying,
+	// looping <video> embeds; Poster also writes its first frame as a JPEG
+	Web    bool
+	Poster bool
+
+	// Contact sheet mode produces a single tiled PNG instead of a GIF
+	ContactSheet bool
+	Rows         int
+	Cols         int
 }
 
 var opts ConvertOptions
@@ -41,15 +220,125 @@ var opts ConvertOptions
 // List of valid video extensions
 var validVideoExtensions = []string{".mp4", ".avi", ".mov", ".mkv", ".webm"}
 
-// isValidVideoFile checks if the file has a valid video extension
-func isValidVideoFile(filePath string) bool {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	for _, validExt := range validVideoExtensions {
-		if ext == validExt {
-			return true
+// validatePaletteFile checks that a palette file exists and is a readable PNG
+func validatePaletteFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "palette file", Path: path}
 		}
+		return fmt.Errorf("failed to open palette file: %w", err)
 	}
-	return false
+	defer f.Close()
+
+	if _, err := png.DecodeConfig(f); err != nil {
+		return fmt.Errorf("palette file is not a valid PNG image: %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// isValidVideoFile checks if the file has a valid video extension
+func isValidVideoFile(filePath string) bool {
+	return slices.Contains(validVideoExtensions, strings.ToLower(filepath.Ext(filePath)))
+}
+
+// List of subtitle file extensions that can be burned in
+var validSubtitleExtensions = []string{".srt", ".ass", ".ssa", ".vtt"}
+
+// isValidSubtitleFile checks if the file has a supported subtitle extension
+func isValidSubtitleFile(filename string) bool {
+	return slices.Contains(validSubtitleExtensions, strings.ToLower(filepath.Ext(filename)))
+}
+
+// List of supported output formats
+var validFormats = []string{gifmaker.FormatGIF, gifmaker.FormatWebM, gifmaker.FormatMP4}
+
+// isValidFormat checks if the output format is supported
+func isValidFormat(format string) bool {
+	return slices.Contains(validFormats, format)
+}
+
+// List of supported frame rate modes
+var validFPSModes = []string{gifmaker.FPSModeCFR, gifmaker.FPSModeVFR, gifmaker.FPSModePassthrough}
+
+// isValidFPSMode checks if the frame rate mode is supported
+func isValidFPSMode(mode string) bool {
+	return slices.Contains(validFPSModes, mode)
+}
+
+// List of supported frame interpolation modes
+var validInterpolateModes = []string{gifmaker.InterpolateBlend, gifmaker.InterpolateMCI}
+
+// isValidInterpolateMode checks if the frame interpolation mode is supported
+func isValidInterpolateMode(mode string) bool {
+	return slices.Contains(validInterpolateModes, mode)
+}
+
+// Demuxers for raw streams that carry no timing, where --input-framerate
+// decides how fast the video plays
+var rawInputFormats = []string{"h264", "hevc", "rawvideo", "mjpeg", "m4v", "mpegvideo", "obu", "vvc", "image2", "image2pipe"}
+
+// isRawInputFormat checks if a demuxer reads raw streams without timing
+func isRawInputFormat(format string) bool {
+	return slices.Contains(rawInputFormats, format)
+}
+
+// List of strengths for the clean-up filters
+var validFilterLevels = []string{gifmaker.LevelLight, gifmaker.LevelMedium, gifmaker.LevelStrong}
+
+// isValidFilterLevel checks if a clean-up filter strength is supported
+func isValidFilterLevel(level string) bool {
+	return slices.Contains(validFilterLevels, level)
+}
+
+// List of color ranges a source can be read as
+var validColorRanges = []string{gifmaker.ColorRangeTV, gifmaker.ColorRangePC}
+
+// isValidColorRange checks if the color range is supported
+func isValidColorRange(colorRange string) bool {
+	return slices.Contains(validColorRanges, colorRange)
+}
+
+// List of HDR tone mapping operators
+var validTonemaps = []string{gifmaker.TonemapHable, gifmaker.TonemapMobius, gifmaker.TonemapReinhard, gifmaker.TonemapClip}
+
+// isValidTonemap checks if the tone mapping operator is supported
+func isValidTonemap(tonemap string) bool {
+	return slices.Contains(validTonemaps, tonemap)
+}
+
+// How much of FFmpeg's log to show with --verbose after a failure
+const ffmpegLogTail = 4000
+
+// Frame rate used when none is given, and the cap for --fps auto
+const (
+	defaultFPS = 10
+	maxAutoFPS = 50
+)
+
+// List of ways to fit a frame into a width x height box
+var validFitModes = []string{gifmaker.FitContain, gifmaker.FitCover, gifmaker.FitStretch}
+
+// isValidFitMode checks if the fit mode is supported
+func isValidFitMode(mode string) bool {
+	return slices.Contains(validFitModes, mode)
+}
+
+// List of supported progress output formats
+var validProgressFormats = []string{progressFormatText, progressFormatJSON}
+
+// isValidProgressFormat checks if the progress format is supported
+func isValidProgressFormat(format string) bool {
+	return slices.Contains(validProgressFormats, format)
+}
+
+// List of supported hardware acceleration methods
+var validHWAccels = []string{"auto", "cuda", "videotoolbox", "vaapi", "none"}
+
+// isValidHWAccel checks if the hardware acceleration method is supported
+func isValidHWAccel(hwaccel string) bool {
+	return slices.Contains(validHWAccels, hwaccel)
 }
 
 var convertCmd = &cobra.Command{
@@ -59,43 +348,711 @@ var convertCmd = &cobra.Command{
 You can either provide options via flags or use interactive mode.
 If no arguments are provided, interactive mode is enabled by default.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Clear interactive history first so the prompts start fresh
+		if opts.Forget {
+			if err := clearHistory(); err != nil {
+				return err
+			}
+			fmt.Println("Cleared remembered inputs and output directories")
+			if opts.Input == "" && !opts.Interactive {
+				return nil
+			}
+		}
+
+		// Validate the output format early since it decides the default extension
+		opts.Format = strings.ToLower(opts.Format)
+		if !isValidFormat(opts.Format) {
+			return usageErrorf("invalid format %q (valid: %s)", opts.Format, strings.Join(validFormats, ", "))
+		}
+		if opts.Web {
+			if cmd.Flags().Changed("format") && opts.Format != gifmaker.FormatMP4 {
+				return usageErrorf("--web writes MP4 and can't be combined with --format %s", opts.Format)
+			}
+			opts.Format = gifmaker.FormatMP4
+		}
+
+		// Validate the frame rate, which may be "auto"
+		if err := setFPS(opts.fpsArg); err != nil {
+			return err
+		}
+
 		// Enable interactive mode automatically if no input is provided
 		if opts.Input == "" && !opts.Interactive {
 			// Check if any arguments or flags were specified
-			if len(args) == 0 && cmd.Flags().NFlag() == 0 {
+			if len(args) == 0 && commandLineFlagCount(cmd.Flags()) == 0 {
 				// No arguments or flags provided, default to interactive mode
 				opts.Interactive = true
 			} else {
-				return fmt.Errorf("input file is required (use --input or -i)")
+				return usageErrorf("input file is required (use --input or -i)")
+			}
+		}
+
+		// If interactive mode is enabled, prompt for values
+		if opts.Interactive {
+			if err := promptForOptions(); err != nil {
+				return fmt.Errorf("error in interactive mode: %w", err)
+			}
+		}
+
+		// Expand ~ and $VARs in paths typed at a prompt or quoted on the
+		// command line
+		opts.Input = expandPath(opts.Input)
+		opts.Output = expandPath(opts.Output)
+
+		// Download remote inputs to a temp file first
+		if isURL(opts.Input) {
+			if opts.Output == "" {
+				opts.Output = defaultOutputPath(remoteFileName(opts.Input))
+			}
+			path, err := downloadInput(opts.Input, opts.DownloadTimeout)
+			if err != nil {
+				return err
+			}
+			defer removeTemp(path)
+			opts.Input = path
+		}
+
+		// Validate input file exists
+		if _, err := os.Stat(opts.Input); os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "input file", Path: opts.Input}
+		}
+
+		// Make sure the input is a decodable video whatever its extension,
+		// unless the demuxer was given explicitly for a raw stream
+		gifInput := opts.InputFormat == "" && isGIFInput(opts.Input)
+		if opts.InputFormat == "" && !gifInput {
+			if err := checkVideoInput(opts.Input); err != nil {
+				return fmt.Errorf("%w (use --input-format for raw streams)", err)
+			}
+		}
+
+		// Set default output if not provided
+		if opts.Output == "" {
+			opts.Output = defaultOutputPath(opts.Input)
+		}
+
+		if err := validateOptions(cmd); err != nil {
+			return err
+		}
+
+		// Check the upload provider's configuration before a long conversion
+		var uploader Uploader
+		if opts.Upload != "" {
+			if len(opts.Sizes) > 0 || opts.Segment > 0 || opts.ByChapters || opts.Format != gifmaker.FormatGIF {
+				return usageErrorf("--upload only supports a single GIF output (not --sizes, --segment, --by-chapters or --format webm)")
+			}
+			u, err := newUploader(opts.Upload)
+			if err != nil {
+				return err
+			}
+			uploader = u
+		}
+
+		// Refuse to clobber existing files unless the user agrees
+		if opts.NoOverwrite && !opts.DryRun {
+			if err := checkExistingOutputs(); err != nil {
+				return err
+			}
+		}
+
+		// Let interactive users check the clip before a long conversion
+		if opts.Interactive && !opts.DryRun {
+			if err := checkFFmpegInstallation(); err != nil {
+				return err
+			}
+			ffmpegPath, err := ffmpegManager.GetPath()
+			if err != nil {
+				return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+			}
+			proceed, err := confirmPreview(ffmpegPath)
+			if err != nil {
+				return err
+			}
+			if !proceed {
+				fmt.Println("Conversion cancelled")
+				return nil
+			}
+		}
+
+		// GIFs are already quantized, so unless asked otherwise only their
+		// frames are trimmed and resized
+		convert := func(ctx context.Context) error {
+			if opts.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+				defer cancel()
+			}
+			result, err := convertVideo(ctx)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &conversionError{fmt.Errorf("conversion timed out after %s (raise or drop --timeout)", opts.Timeout)}
+			}
+			if err != nil || result == nil {
+				return err
+			}
+			printConversionResult(result)
+			return nil
+		}
+		if gifInput && !opts.ForceReencode {
+			if flag := fullPipelineFlag(cmd); flag != "" {
+				warning := fmt.Sprintf("%s is already a GIF; --%s needs the full pipeline, so its colors will be quantized again", opts.Input, flag)
+				color.Yellow("⚠️ %s", warning)
+				GetLogger().Warn(warning)
+			} else {
+				color.Yellow("⚠️ %s is already a GIF; keeping its palette instead of generating a new one (use --force-reencode to re-encode it)", opts.Input)
+				convert = func(context.Context) error { return convertGIFInput() }
+			}
+		}
+		for {
+			// Interactive users can tweak the result and convert again
+			prev := opts
+			if err := convert(cmd.Context()); err != nil {
+				return err
+			}
+			if !opts.Interactive || opts.DryRun {
+				break
+			}
+			redo, err := promptRedo(prev)
+			if err != nil {
+				return err
+			}
+			if !redo {
+				break
+			}
+		}
+
+		if opts.DryRun {
+			return nil
+		}
+
+		if opts.Poster {
+			if err := writePoster(); err != nil {
+				return err
 			}
 		}
 
-		// If interactive mode is enabled, prompt for values
-		if opts.Interactive {
-			if err := promptForOptions(); err != nil {
-				return fmt.Errorf("error in interactive mode: %w", err)
-			}
+		// An uploaded GIF's URL is copied instead of its path
+		if uploader != nil {
+			return uploadOutput(cmd.Context(), uploader, opts.Output)
+		}
+		if opts.Clipboard {
+			copyOutputPaths()
+		}
+		return nil
+	},
+}
+
+// validateOptions checks the convert flags against each other once the
+// input and output are known, and fills in the values derived from them
+func validateOptions(cmd *cobra.Command) error {
+	// Validate the forced input frame rate
+	if opts.InputFrameRate != "" {
+		if rate, err := parseFrameRate(opts.InputFrameRate); err != nil || rate <= 0 {
+			return usageErrorf("invalid input frame rate %q (expected e.g. 30 or 30000/1001)", opts.InputFrameRate)
+		}
+		if !isRawInputFormat(opts.InputFormat) {
+			GetLogger().Warnf("--input-framerate only affects raw input formats (%s) and may be ignored", strings.Join(rawInputFormats, ", "))
+		}
+	}
+
+	// Validate the clip range
+	for _, t := range []struct{ name, value string }{
+		{"start", opts.Start},
+		{"duration", opts.Duration},
+		{"end", opts.End},
+	} {
+		if _, err := gifmaker.TimeToSeconds(t.value); err != nil {
+			return usageErrorf("--%s: %w (expected HH:MM:SS, MM:SS or seconds)", t.name, err)
+		}
+	}
+	if opts.End != "" && opts.Duration != "" {
+		return usageErrorf("--end and --duration cannot be used together")
+	}
+	if opts.End != "" {
+		if _, err := clipDurationFromEnd(opts.Start, opts.End); err != nil {
+			return asUsageError(err)
+		}
+	}
+	if opts.SeekAccurate {
+		if opts.FastSeek {
+			return usageErrorf("--seek-accurate and --fast-seek cannot be used together")
+		}
+		if opts.Start == "" {
+			GetLogger().Warn("--seek-accurate has no effect without --start")
+		}
+	}
+
+	// Validate the output size
+	if opts.Width < 0 || opts.Height < 0 {
+		return usageErrorf("width and height cannot be negative (got %dx%d)", opts.Width, opts.Height)
+	}
+	if !isValidFitMode(opts.Fit) {
+		return usageErrorf("invalid fit mode %q (valid: %s)", opts.Fit, strings.Join(validFitModes, ", "))
+	}
+	if opts.Height > 0 && (opts.Aspect != "" || len(opts.Sizes) > 0) {
+		return usageErrorf("--height cannot be combined with --aspect or --sizes")
+	}
+
+	// Validate quality
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return usageErrorf("quality must be between 1 and 100 (got %d)", opts.Quality)
+	}
+
+	// Validate frame delay
+	if opts.Delay < 0 {
+		return usageErrorf("frame delay cannot be negative (got %d)", opts.Delay)
+	}
+
+	// Validate frame rate mode
+	if !isValidFPSMode(opts.FPSMode) {
+		return usageErrorf("invalid fps mode %q (valid: %s)", opts.FPSMode, strings.Join(validFPSModes, ", "))
+	}
+	if opts.FPSMode != gifmaker.FPSModeCFR && cmd.Flags().Changed("fps") {
+		GetLogger().Warnf("--fps is ignored with --fps-mode %s", opts.FPSMode)
+	}
+
+	// Validate frame interpolation, which needs a target frame rate
+	opts.Interpolate = strings.ToLower(opts.Interpolate)
+	if opts.Interpolate != "" {
+		if !isValidInterpolateMode(opts.Interpolate) {
+			return usageErrorf("invalid interpolation mode %q (valid: %s)", opts.Interpolate, strings.Join(validInterpolateModes, ", "))
+		}
+		if opts.FPSMode != gifmaker.FPSModeCFR {
+			return usageErrorf("--interpolate needs --fps-mode cfr to know which frame rate to interpolate to")
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 {
+			return usageErrorf("--interpolate cannot be combined with --sample-frames or --scene-threshold")
+		}
+	}
+
+	// Validate the frame rate clamps
+	if opts.MinFPS < 0 || opts.MaxFPS < 0 {
+		return usageErrorf("--min-fps and --max-fps cannot be negative")
+	}
+	if opts.MinFPS > 0 && opts.MaxFPS > 0 && opts.MinFPS > opts.MaxFPS {
+		return usageErrorf("--min-fps (%d) cannot be greater than --max-fps (%d)", opts.MinFPS, opts.MaxFPS)
+	}
+
+	// Validate hardware acceleration method
+	if !isValidHWAccel(opts.HWAccel) {
+		return usageErrorf("invalid hwaccel value %q (valid: %s)", opts.HWAccel, strings.Join(validHWAccels, ", "))
+	}
+
+	// Use the computed thread count unless the user picked one
+	if !cmd.Flags().Changed("threads") {
+		opts.Threads = GetOptimalThreads()
+	} else if opts.Threads < 0 {
+		return usageErrorf("thread count cannot be negative (got %d)", opts.Threads)
+	}
+
+	// Validate the resource limits
+	if opts.Timeout < 0 {
+		return usageErrorf("--timeout cannot be negative (got %s)", opts.Timeout)
+	}
+	if opts.Nice < 0 || opts.Nice > 19 {
+		return usageErrorf("--nice must be between 0 and 19 (got %d)", opts.Nice)
+	}
+	if opts.Nice > 0 && runtime.GOOS == "windows" {
+		GetLogger().Warn("--nice is only supported on Unix; running FFmpeg at normal priority")
+		opts.Nice = 0
+	}
+
+	// Validate parallel chunk count
+	if opts.Parallel < 1 {
+		return usageErrorf("parallel chunk count must be at least 1 (got %d)", opts.Parallel)
+	}
+	if opts.Parallel > 1 && opts.HWAccel != "none" {
+		return usageErrorf("--hwaccel cannot be combined with --parallel")
+	}
+
+	if opts.FFmpegVerbose && (opts.Parallel > 1 || opts.ContactSheet) {
+		GetLogger().Warn("--ffmpeg-verbose only applies to single-pass conversions and is ignored")
+	}
+
+	if !isValidProgressFormat(opts.ProgressFormat) {
+		return usageErrorf("invalid progress format %q (valid: %s)", opts.ProgressFormat, strings.Join(validProgressFormats, ", "))
+	}
+	if opts.ProgressFormat == progressFormatJSON && (opts.Parallel > 1 || opts.FFmpegVerbose || opts.NoProgress) {
+		return usageErrorf("--progress-format json cannot be combined with --parallel, --ffmpeg-verbose or --no-progress")
+	}
+
+	if opts.Resume && opts.Parallel < 2 {
+		GetLogger().Warn("--resume only applies to --parallel conversions and is ignored")
+	}
+
+	// Validate sample mode
+	if opts.SampleFrames < 0 {
+		return usageErrorf("sample frame count cannot be negative (got %d)", opts.SampleFrames)
+	}
+	if opts.SampleFrames > 0 {
+		if opts.FrameHold <= 0 {
+			return usageErrorf("frame hold must be greater than 0 seconds (got %g)", opts.FrameHold)
+		}
+		if opts.Parallel > 1 {
+			return usageErrorf("--sample-frames cannot be combined with --parallel")
+		}
+	}
+
+	// Validate pauses
+	if opts.StartPause < 0 || opts.EndPause < 0 {
+		return usageErrorf("pause durations cannot be negative (got --start-pause %g, --end-pause %g)", opts.StartPause, opts.EndPause)
+	}
+	if (opts.StartPause > 0 || opts.EndPause > 0) && (opts.Parallel > 1 || opts.SampleFrames > 0 || opts.Segment > 0 || opts.ByChapters) {
+		return usageErrorf("--start-pause and --end-pause cannot be combined with --parallel, --sample-frames, --segment or --by-chapters")
+	}
+
+	// Validate scene mode
+	if opts.SceneThreshold < 0 || opts.SceneThreshold > 1 {
+		return usageErrorf("scene threshold must be between 0 and 1 (got %g)", opts.SceneThreshold)
+	}
+	if opts.SceneThreshold > 0 {
+		if opts.FrameHold <= 0 {
+			return usageErrorf("frame hold must be greater than 0 seconds (got %g)", opts.FrameHold)
+		}
+		if opts.SampleFrames > 0 || opts.Parallel > 1 {
+			return usageErrorf("--scene-threshold cannot be combined with --sample-frames or --parallel")
+		}
+	}
+
+	// Validate frame de-duplication
+	if opts.Dedupe {
+		if opts.DedupeThreshold <= 0 {
+			return usageErrorf("dedupe threshold must be greater than 0 (got %g)", opts.DedupeThreshold)
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 {
+			return usageErrorf("--dedupe cannot be combined with --sample-frames or --scene-threshold")
+		}
+	} else if cmd.Flags().Changed("dedupe-threshold") {
+		GetLogger().Warn("--dedupe-threshold has no effect without --dedupe")
+	}
+
+	// Validate the speed keyframes
+	if opts.SpeedCurve != "" {
+		curve, err := gifmaker.ParseSpeedCurve(opts.SpeedCurve)
+		if err != nil {
+			return usageErrorf("--speed-curve: %w", err)
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 || opts.Parallel > 1 || opts.Segment > 0 || opts.ByChapters {
+			return usageErrorf("--speed-curve cannot be combined with --sample-frames, --scene-threshold, --parallel, --segment or --by-chapters")
+		}
+		opts.speedCurve = curve
+	}
+
+	// Validate the ranges to join
+	if len(opts.Ranges) > 0 {
+		ranges, err := gifmaker.ParseTimeRanges(opts.Ranges)
+		if err != nil {
+			return usageErrorf("--ranges: %w", err)
+		}
+		if opts.Start != "" || opts.Duration != "" || opts.End != "" || opts.AutoTrim {
+			return usageErrorf("--ranges replaces --start, --duration, --end and --auto-trim")
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 || opts.Parallel > 1 || opts.Segment > 0 || opts.ByChapters || opts.ContactSheet {
+			return usageErrorf("--ranges cannot be combined with --sample-frames, --scene-threshold, --parallel, --segment, --by-chapters or --contact-sheet")
+		}
+		opts.timeRanges = ranges
+	}
+
+	// Validate the subtitle file
+	if opts.Subtitles != "" {
+		opts.Subtitles = expandPath(opts.Subtitles)
+		if _, err := os.Stat(opts.Subtitles); err != nil {
+			return &inputNotFoundError{Kind: "subtitle file", Path: opts.Subtitles}
+		}
+		if !isValidSubtitleFile(opts.Subtitles) {
+			return usageErrorf("subtitle file must be one of %s: %s", strings.Join(validSubtitleExtensions, ", "), opts.Subtitles)
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 || opts.Parallel > 1 {
+			return usageErrorf("--subtitles cannot be combined with --sample-frames, --scene-threshold or --parallel")
+		}
+	} else if opts.SubtitleStyle != "" {
+		GetLogger().Warn("--subtitle-style has no effect without --subtitles")
+	}
+
+	// Validate the audio visualization. It is drawn against the
+	// source's timestamps, so nothing may re-time the frames.
+	if opts.Waveform != "" {
+		opts.Waveform = strings.ToLower(opts.Waveform)
+		opts.WaveformPosition = strings.ToLower(opts.WaveformPosition)
+		if !isValidWaveformMode(opts.Waveform) {
+			return usageErrorf("invalid waveform %q (valid: %s)", opts.Waveform, strings.Join(validWaveformModes, ", "))
+		}
+		if !isValidWaveformPosition(opts.WaveformPosition) {
+			return usageErrorf("invalid waveform position %q (valid: %s)", opts.WaveformPosition, strings.Join(validWaveformPositions, ", "))
+		}
+		if opts.WaveformHeight < 1 || opts.WaveformHeight > 100 {
+			return usageErrorf("waveform height must be between 1 and 100 percent (got %d)", opts.WaveformHeight)
+		}
+		if len(opts.Sizes) > 0 || opts.Parallel > 1 || opts.ContactSheet {
+			return usageErrorf("--waveform cannot be combined with --sizes, --parallel or --contact-sheet")
+		}
+		if opts.SampleFrames > 0 || opts.SceneThreshold > 0 || opts.SpeedCurve != "" || len(opts.Ranges) > 0 ||
+			opts.Dedupe || opts.StartPause > 0 || opts.EndPause > 0 || opts.Delay > 0 {
+			return usageErrorf("--waveform cannot be combined with options that re-time frames (--sample-frames, --scene-threshold, --speed-curve, --ranges, --dedupe, --start-pause, --end-pause, --delay)")
+		}
+		if cmd.Flags().Changed("waveform-color") && opts.Waveform != gifmaker.WaveformWaves {
+			GetLogger().Warn("--waveform-color only applies to --waveform waves and is ignored")
+		}
+
+		// If ffprobe can't tell, FFmpeg reports the missing stream itself
+		hasAudio, err := hasAudioStream(opts.Input)
+		if err != nil {
+			GetLogger().Debugf("Could not check %s for audio: %v", opts.Input, err)
+		} else if !hasAudio {
+			return usageErrorf("--waveform needs an audio stream, but %s has none", opts.Input)
+		}
+	} else {
+		for _, name := range []string{"waveform-position", "waveform-height", "waveform-color"} {
+			if cmd.Flags().Changed(name) {
+				GetLogger().Warnf("--%s has no effect without --waveform", name)
+			}
+		}
+	}
+
+	// Validate multi-size output widths
+	for _, w := range opts.Sizes {
+		if w < 1 {
+			return usageErrorf("invalid width in --sizes: %d", w)
+		}
+	}
+	if len(opts.Sizes) > 0 && opts.Parallel > 1 {
+		return usageErrorf("--sizes cannot be combined with --parallel")
+	}
+
+	// Validate clean-up filter levels
+	for _, f := range []struct{ name, value string }{
+		{"denoise", opts.Denoise},
+		{"deband", opts.Deband},
+	} {
+		if f.value != "" && !isValidFilterLevel(f.value) {
+			return usageErrorf("invalid --%s level %q (valid: %s)", f.name, f.value, strings.Join(validFilterLevels, ", "))
+		}
+	}
+
+	// Validate the color handling
+	opts.ColorRange = strings.ToLower(opts.ColorRange)
+	if opts.ColorRange != "" && !isValidColorRange(opts.ColorRange) {
+		return usageErrorf("invalid color range %q (valid: %s)", opts.ColorRange, strings.Join(validColorRanges, ", "))
+	}
+	opts.Tonemap = strings.ToLower(opts.Tonemap)
+	if opts.Tonemap != "" {
+		if !isValidTonemap(opts.Tonemap) {
+			return usageErrorf("invalid tonemap %q (valid: %s)", opts.Tonemap, strings.Join(validTonemaps, ", "))
+		}
+		if opts.PreserveAlpha {
+			return usageErrorf("--tonemap can't be combined with --preserve-alpha")
+		}
+	}
+
+	if opts.Sharpen < 0 || opts.Sharpen > 10 {
+		return usageErrorf("sharpen intensity must be between 0 and 10 (got %d)", opts.Sharpen)
+	}
+
+	// Validate orientation transforms
+	if opts.Rotate != 0 && opts.Rotate != 90 && opts.Rotate != 180 && opts.Rotate != 270 {
+		return usageErrorf("invalid rotation %d (valid: 0, 90, 180, 270)", opts.Rotate)
+	}
+	if opts.Flip != "" && opts.Flip != gifmaker.FlipHorizontal && opts.Flip != gifmaker.FlipVertical {
+		return usageErrorf("invalid flip %q (valid: h, v)", opts.Flip)
+	}
+
+	// Validate the manual crop, as suggested by info --suggest-crop
+	if opts.Crop != "" {
+		if opts.Autocrop {
+			return usageErrorf("--crop and --autocrop cannot be used together")
+		}
+		opts.crop = strings.TrimPrefix(opts.Crop, "crop=")
+		if _, _, _, _, err := parseCropRect(opts.crop); err != nil {
+			return usageErrorf("invalid crop %q (expected W:H:X:Y)", opts.Crop)
+		}
+	}
+
+	// Validate the aspect ratio
+	if opts.Aspect != "" {
+		if _, _, err := parseAspect(opts.Aspect); err != nil {
+			return asUsageError(err)
+		}
+		if !isValidAspectMode(opts.AspectMode) {
+			return usageErrorf("invalid aspect mode %q (valid: %s)", opts.AspectMode, strings.Join(validAspectModes, ", "))
+		}
+	}
+
+	if opts.AutoTrim && opts.ByChapters {
+		return usageErrorf("--auto-trim cannot be combined with --by-chapters")
+	}
+	if opts.AutoTrimThreshold <= 0 || opts.AutoTrimThreshold >= 1 {
+		return usageErrorf("invalid auto trim threshold %g (expected a value between 0 and 1)", opts.AutoTrimThreshold)
+	}
+
+	if opts.AutoStatsMode && (opts.PaletteFile != "" || opts.Parallel > 1 || opts.Format != gifmaker.FormatGIF) {
+		return usageErrorf("--auto-stats-mode cannot be combined with --palette-file, --parallel or --format webm")
+	}
+
+	// Validate the paletteuse fine-tuning
+	if name := changedPaletteUseFlag(cmd); name != "" && opts.Format != gifmaker.FormatGIF {
+		return usageErrorf("--%s only applies to GIF output", name)
+	}
+	if cmd.Flags().Changed("palette-dither-scale") {
+		if opts.DitherScale < 0 || opts.DitherScale > 5 {
+			return usageErrorf("invalid dither scale %d (expected 0-5)", opts.DitherScale)
+		}
+		opts.dither = gifmaker.BayerDither(opts.DitherScale)
+	}
+	if !isValidDiffMode(opts.DiffMode) {
+		return usageErrorf("invalid diff mode %q (valid: %s)", opts.DiffMode, strings.Join(validDiffModes, ", "))
+	}
+	if opts.AlphaThreshold < 1 || opts.AlphaThreshold > 255 {
+		return usageErrorf("invalid alpha threshold %d (expected 1-255)", opts.AlphaThreshold)
+	}
+	if cmd.Flags().Changed("palette-alpha-threshold") && !opts.PreserveAlpha {
+		return usageErrorf("--palette-alpha-threshold only applies with --preserve-alpha")
+	}
+	// A new palette per frame also needs palettegen to make one per frame
+	if opts.NewPalette {
+		if opts.AutoStatsMode || opts.PaletteFile != "" || opts.Parallel > 1 {
+			return usageErrorf("--palette-new cannot be combined with --auto-stats-mode, --palette-file or --parallel")
+		}
+		opts.statsMode = gifmaker.StatsModeSingle
+	}
+
+	// Validate the user-supplied palette
+	if opts.PaletteFile != "" {
+		if err := validatePaletteFile(opts.PaletteFile); err != nil {
+			return asUsageError(err)
+		}
+	}
+
+	// Video output has no palette and can't be joined from GIF chunks
+	if opts.Format != gifmaker.FormatGIF {
+		if opts.PaletteFile != "" {
+			return usageErrorf("--palette-file only applies to GIF output")
+		}
+		if opts.Parallel > 1 {
+			return usageErrorf("--parallel only supports GIF output")
+		}
+	}
+	// H.264 in yuv420p has no alpha channel
+	if opts.Format == gifmaker.FormatMP4 && opts.PreserveAlpha {
+		return usageErrorf("--preserve-alpha isn't supported with MP4 output (use --format webm)")
+	}
+
+	// Validate segment mode
+	if opts.Segment < 0 {
+		return usageErrorf("segment length cannot be negative (got %g)", opts.Segment)
+	}
+	if opts.Segment > 0 && opts.ByChapters {
+		return usageErrorf("--segment and --by-chapters cannot be used together")
+	}
+	if (opts.Segment > 0 || opts.ByChapters) && (opts.Parallel > 1 || len(opts.Sizes) > 0 || opts.SampleFrames > 0 || opts.ContactSheet) {
+		return usageErrorf("--segment and --by-chapters cannot be combined with --parallel, --sizes, --sample-frames or --contact-sheet")
+	}
+	if opts.ByChapters && (opts.Start != "" || opts.Duration != "" || opts.End != "") {
+		return usageErrorf("--by-chapters converts whole chapters and cannot be combined with --start, --duration or --end")
+	}
+
+	// Validate contact sheet grid
+	if opts.ContactSheet && (opts.Rows < 1 || opts.Cols < 1) {
+		return usageErrorf("contact sheet rows and cols must be at least 1 (got %dx%d)", opts.Rows, opts.Cols)
+	}
+
+	if opts.Clipboard && (opts.Segment > 0 || opts.ByChapters) {
+		return usageErrorf("--clipboard can't be combined with --segment or --by-chapters")
+	}
+
+	if opts.Poster && (len(opts.Sizes) > 0 || opts.Segment > 0 || opts.ByChapters || opts.ContactSheet) {
+		return usageErrorf("--poster needs a single output (not --sizes, --segment, --by-chapters or --contact-sheet)")
+	}
+
+	if opts.Analyze && (opts.Format != gifmaker.FormatGIF || opts.Segment > 0 || opts.ByChapters || opts.ContactSheet) {
+		return usageErrorf("--analyze only supports GIF output (not --format webm, --segment, --by-chapters or --contact-sheet)")
+	}
+
+	if opts.Comment != "" && (opts.Segment > 0 || opts.ByChapters || opts.ContactSheet) {
+		return usageErrorf("--comment can't be combined with --segment, --by-chapters or --contact-sheet")
+	}
+
+	if opts.OptimizeGo {
+		if opts.Format != gifmaker.FormatGIF || opts.Segment > 0 || opts.ByChapters || opts.ContactSheet {
+			return usageErrorf("--optimize-go only supports GIF output (not --format webm, --segment, --by-chapters or --contact-sheet)")
+		}
+		if opts.PreserveAlpha {
+			return usageErrorf("--optimize-go can't be combined with --preserve-alpha")
+		}
+	}
+
+	return nil
+}
+
+// checkExistingOutputs returns an error if --no-overwrite is set and an
+// output file already exists. In interactive mode the user is asked instead.
+func checkExistingOutputs() error {
+	approved := false
+	for _, output := range conversionOutputs() {
+		if _, err := os.Stat(output); err != nil {
+			continue
 		}
 
-		// Validate input file exists
-		if _, err := os.Stat(opts.Input); os.IsNotExist(err) {
-			return fmt.Errorf("input file does not exist: %s", opts.Input)
+		if !opts.Interactive {
+			return fmt.Errorf("output file already exists: %s (remove it or drop --no-overwrite)", output)
 		}
 
-		// Validate input file has a valid video extension
-		if !isValidVideoFile(opts.Input) {
-			return fmt.Errorf("input file must be a valid video format (mp4, avi, mov, mkv, webm): %s", opts.Input)
+		overwrite := false
+		question := &survey.Confirm{
+			Message: fmt.Sprintf("%s already exists. Overwrite it?", output),
+			Default: false,
+		}
+		if err := survey.AskOne(question, &overwrite); err != nil {
+			return err
 		}
+		if !overwrite {
+			return fmt.Errorf("not overwriting existing file: %s", output)
+		}
+		approved = true
+	}
 
-		// Set default output if not provided
-		if opts.Output == "" {
-			inputBase := filepath.Base(opts.Input)
-			inputExt := filepath.Ext(inputBase)
-			opts.Output = strings.TrimSuffix(inputBase, inputExt) + ".gif"
+	// The user agreed to replace the existing files, so let FFmpeg do it
+	if approved {
+		opts.NoOverwrite = false
+	}
+	return nil
+}
+
+// copyOutputPaths copies the absolute paths of the outputs to the
+// clipboard, warning if that isn't possible
+func copyOutputPaths() {
+	outputs := conversionOutputs()
+	for i, output := range outputs {
+		if abs, err := filepath.Abs(output); err == nil {
+			outputs[i] = abs
 		}
+	}
 
-		return convertVideo()
-	},
+	if err := copyToClipboard(strings.Join(outputs, "\n")); err != nil {
+		color.Yellow("⚠️ Could not copy to clipboard: %v", err)
+		GetLogger().Warnf("Could not copy to clipboard: %v", err)
+		return
+	}
+	fmt.Println("Output path copied to clipboard")
+}
+
+// defaultOutputPath derives the output filename from the input filename
+func defaultOutputPath(input string) string {
+	inputBase := filepath.Base(input)
+	inputExt := filepath.Ext(inputBase)
+	outputExt := ".gif"
+	switch opts.Format {
+	case gifmaker.FormatWebM:
+		outputExt = ".webm"
+	case gifmaker.FormatMP4:
+		outputExt = ".mp4"
+	}
+	if opts.ContactSheet {
+		outputExt = ".png"
+	}
+	// Don't default to overwriting an input that's already in the output format
+	if strings.EqualFold(inputExt, outputExt) {
+		return strings.TrimSuffix(inputBase, inputExt) + "-optimized" + outputExt
+	}
+	return strings.TrimSuffix(inputBase, inputExt) + outputExt
 }
 
 // Add FFmpeg manager variable
@@ -103,24 +1060,107 @@ var ffmpegManager *ffmpeg.Manager
 
 // Update the init function to initialize the FFmpeg manager
 func init() {
-	convertCmd.Flags().StringVarP(&opts.Input, "input", "i", "", "Input video file (required unless using interactive mode)")
+	convertCmd.Flags().StringVarP(&opts.Input, "input", "i", "", "Input video file or http(s) URL (required unless using interactive mode)")
 	convertCmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Output GIF file (default: input_name.gif)")
-	convertCmd.Flags().IntVarP(&opts.FPS, "fps", "f", 10, "Frames per second")
+	convertCmd.Flags().StringVarP(&opts.fpsArg, "fps", "f", "10", "Frames per second, or auto to match the source")
+	convertCmd.Flags().IntVar(&opts.MinFPS, "min-fps", 0, "Never convert at fewer frames per second than this")
+	convertCmd.Flags().IntVar(&opts.MaxFPS, "max-fps", 0, "Never convert at more frames per second than this")
+	convertCmd.Flags().IntVar(&opts.Delay, "delay", 0, "GIF frame delay in centiseconds, overriding the delay derived from --fps")
+	convertCmd.Flags().StringVar(&opts.Interpolate, "interpolate", "", "Make up in-between frames to reach --fps on low frame rate sources (blend, mci); CPU-heavy")
+	convertCmd.Flags().StringVar(&opts.FPSMode, "fps-mode", "cfr", "Frame timing: cfr resamples to --fps, vfr and passthrough keep the source timing (for variable-frame-rate videos)")
 	convertCmd.Flags().StringVar(&opts.Start, "start", "", "Start time (format: 00:00:00)")
 	convertCmd.Flags().StringVar(&opts.Duration, "duration", "", "Duration (format: 00:00:00)")
+	convertCmd.Flags().StringVar(&opts.End, "end", "", "End time (format: 00:00:00); alternative to --duration")
+	convertCmd.Flags().BoolVar(&opts.AutoTrim, "auto-trim", false, "Detect static frames at the start and end of the clip (e.g. in screen recordings) and trim them")
+	convertCmd.Flags().Float64Var(&opts.AutoTrimThreshold, "auto-trim-threshold", defaultAutoTrimThreshold, "How much frames may differ (0-1) and still count as static for --auto-trim")
+	convertCmd.Flags().BoolVar(&opts.FastSeek, "fast-seek", false, "Seek to --start on the input side: much faster on long videos, but may start at the nearest keyframe instead of the exact time")
+	convertCmd.Flags().BoolVar(&opts.SeekAccurate, "seek-accurate", false, "Seek to --start in two steps: a fast seek to a second or two before it, then an exact one; as fast as --fast-seek on long videos and frame accurate")
+	convertCmd.Flags().Float64Var(&opts.StartPause, "start-pause", 0, "Hold the first frame for this many seconds")
+	convertCmd.Flags().Float64Var(&opts.EndPause, "end-pause", 0, "Hold the last frame for this many seconds before the GIF loops")
 	convertCmd.Flags().IntVarP(&opts.Width, "width", "w", 0, "Output width in pixels (default: same as input)")
+	convertCmd.Flags().IntVar(&opts.Height, "height", 0, "Output height in pixels (default: keep the aspect ratio)")
+	convertCmd.Flags().BoolVar(&opts.ForceEvenDimensions, "force-even-dimensions", false, "Round the output width and height to even numbers, as some encoders and filters need (always on for MP4)")
+	convertCmd.Flags().StringVar(&opts.Fit, "fit", gifmaker.FitContain, "How to fit the frame when both --width and --height are set (contain, cover, stretch)")
 	convertCmd.Flags().IntVarP(&opts.Quality, "quality", "q", 90, "Output quality (1-100)")
+	convertCmd.Flags().StringVar(&opts.Format, "format", "gif", "Output format (gif, webm, mp4); webm and mp4 are video and much smaller")
 	convertCmd.Flags().BoolVarP(&opts.Interactive, "interactive", "I", false, "Use interactive mode (default if no arguments provided)")
 	convertCmd.Flags().BoolVar(&opts.NoProgress, "no-progress", false, "Disable progress bar")
+	convertCmd.Flags().StringVar(&opts.ProgressFormat, "progress-format", progressFormatText, "Progress output: text (progress bar) or json (one JSON object per update on stderr)")
+	convertCmd.Flags().BoolVar(&opts.FFmpegVerbose, "ffmpeg-verbose", false, "Show FFmpeg's full log output live instead of the progress bar")
+	convertCmd.Flags().BoolVar(&opts.NoOverwrite, "no-overwrite", false, "Don't replace an existing output file (asks first in interactive mode)")
+	convertCmd.Flags().StringVar(&opts.InputFormat, "input-format", "", "Force the FFmpeg demuxer for inputs it can't detect, e.g. h264 or rawvideo")
+	convertCmd.Flags().StringVar(&opts.InputFrameRate, "input-framerate", "", "Frame rate of a raw input stream, e.g. 30 or 30000/1001")
+	convertCmd.Flags().StringVar(&opts.HWAccel, "hwaccel", "none", "Hardware-accelerated decoding (auto, cuda, videotoolbox, vaapi, none); only speeds up decoding")
+	convertCmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Stop FFmpeg and fail if the conversion runs longer than this, e.g. 10m (default: no limit)")
+	convertCmd.Flags().IntVar(&opts.Nice, "nice", 0, "Run FFmpeg at a lower CPU priority, from 1 to 19 (Unix only)")
+	convertCmd.Flags().IntVar(&opts.Threads, "threads", 0, "FFmpeg threads (default: number of CPU cores minus 2; 0 lets FFmpeg decide)")
+	convertCmd.Flags().IntVar(&opts.Parallel, "parallel", 1, "Experimental: split the clip into N chunks and convert them concurrently")
+	convertCmd.Flags().BoolVar(&opts.Resume, "resume", false, "With --parallel, keep the palette and finished chunks in the cache so an interrupted run can continue")
+	convertCmd.Flags().StringVar(&opts.PaletteFile, "palette-file", "", "Use a precomputed palette PNG instead of generating one")
+	convertCmd.Flags().BoolVar(&opts.AutoStatsMode, "auto-stats-mode", false, "Sample the clip and pick the palette stats mode (diff, full or single) from how much its colors change")
+	convertCmd.Flags().IntVar(&opts.DitherScale, "palette-dither-scale", 0, "Use ordered (bayer) dithering with this scale, from 0 (strongest pattern) to 5 (least visible, more banding), instead of the one --quality picks")
+	convertCmd.Flags().StringVar(&opts.DiffMode, "palette-diff-mode", gifmaker.DiffModeRectangle, "Which part of each frame is re-dithered: rectangle (only what changed) or none (every pixel)")
+	convertCmd.Flags().BoolVar(&opts.NewPalette, "palette-new", false, "Give every frame its own palette (better colors, larger file)")
+	convertCmd.Flags().IntVar(&opts.AlphaThreshold, "palette-alpha-threshold", gifmaker.AlphaThreshold, "With --preserve-alpha, pixels with alpha below this (1-255) become transparent")
+	convertCmd.Flags().IntVar(&opts.SampleFrames, "sample-frames", 0, "Build a slideshow GIF from N evenly-spaced frames of the clip")
+	convertCmd.Flags().Float64Var(&opts.FrameHold, "frame-hold", 0.5, "Seconds to show each frame in --sample-frames and --scene-threshold modes")
+	convertCmd.Flags().Float64Var(&opts.SceneThreshold, "scene-threshold", 0, "Build a highlight GIF from frames where the scene changes by more than this (0-1)")
+	convertCmd.Flags().StringVar(&opts.Subtitles, "subtitles", "", "Burn in captions from a subtitle file (srt, ass, ssa, vtt)")
+	convertCmd.Flags().StringVar(&opts.SubtitleStyle, "subtitle-style", "", "ASS style overrides for --subtitles, e.g. FontSize=24,PrimaryColour=&H00FFFF&")
+	convertCmd.Flags().StringSliceVar(&opts.Ranges, "ranges", nil, "Join several parts of the video into one GIF, as comma-separated start-end pairs (e.g. 0-2,10-12)")
+	convertCmd.Flags().StringVar(&opts.SpeedCurve, "speed-curve", "", "Change the playback speed over the clip with time:factor keyframes, e.g. 0:1,3:4 (a single factor is a constant speed)")
+	convertCmd.Flags().BoolVar(&opts.Dedupe, "dedupe", false, "Drop near-duplicate frames (e.g. static parts of screen recordings)")
+	convertCmd.Flags().Float64Var(&opts.DedupeThreshold, "dedupe-threshold", 1, "How different frames must be to be kept with --dedupe, relative to FFmpeg's mpdecimate defaults; higher drops more")
+	convertCmd.Flags().IntSliceVar(&opts.Sizes, "sizes", nil, "Comma-separated output widths to produce from one decode (e.g. 480,320,160)")
+	convertCmd.Flags().BoolVar(&opts.Forget, "forget", false, "Clear the remembered recent inputs and output directories")
+	convertCmd.Flags().IntVar(&opts.Rotate, "rotate", 0, "Rotate the video clockwise by 90, 180 or 270 degrees")
+	convertCmd.Flags().BoolVar(&opts.NoAutorotate, "no-autorotate", false, "Ignore rotation metadata in the source (e.g. from phones) and keep the stored orientation")
+	convertCmd.Flags().StringVar(&opts.Flip, "flip", "", "Mirror the video horizontally (h) or vertically (v)")
+	convertCmd.Flags().BoolVar(&opts.Autocrop, "autocrop", false, "Detect and crop black bars (letterboxing) automatically")
+	convertCmd.Flags().StringVar(&opts.Crop, "crop", "", "Crop to a W:H:X:Y rectangle before scaling (see info --suggest-crop)")
+	convertCmd.Flags().StringVar(&opts.ColorRange, "color-range", "", "Read the source as limited (tv) or full (pc) range, for sources that are tagged wrong or not at all")
+	convertCmd.Flags().StringVar(&opts.Tonemap, "tonemap", "", "Convert HDR sources to SDR with this operator (hable, mobius, reinhard, clip); needs FFmpeg with zscale")
+	convertCmd.Flags().StringVar(&opts.Denoise, "denoise", "", "Reduce noise before building the palette (light, medium, strong); slows conversion")
+	convertCmd.Flags().StringVar(&opts.Deband, "deband", "", "Smooth color banding before building the palette (light, medium, strong); slows conversion")
+	convertCmd.Flags().IntVar(&opts.Sharpen, "sharpen", 0, "Sharpen the frames after scaling, from 1 (subtle) to 10 (strong)")
+	convertCmd.Flags().StringVar(&opts.Aspect, "aspect", "", "Output aspect ratio, e.g. 1:1, 4:5, 16:9 or 9:16")
+	convertCmd.Flags().StringVar(&opts.AspectMode, "aspect-mode", "crop", "How to reach --aspect: crop the edges or pad with black bars (crop, pad)")
+	convertCmd.Flags().StringVar(&opts.Waveform, "waveform", "", "Overlay the audio as a music visualizer: waves or spectrum (the input needs an audio stream)")
+	convertCmd.Flags().StringVar(&opts.WaveformPosition, "waveform-position", gifmaker.WaveformBottom, "Where to draw the --waveform band (bottom, top, center)")
+	convertCmd.Flags().IntVar(&opts.WaveformHeight, "waveform-height", gifmaker.DefaultWaveformHeight, "Height of the --waveform band in percent of the frame height")
+	convertCmd.Flags().StringVar(&opts.WaveformColor, "waveform-color", "white", "Color of --waveform waves, an FFmpeg color name or hex value such as 0x00ff00, optionally with @opacity")
+	convertCmd.Flags().BoolVar(&opts.PreserveAlpha, "preserve-alpha", false, "Keep transparency from sources with an alpha channel (GIF transparency is on/off per pixel)")
+	convertCmd.Flags().DurationVar(&opts.DownloadTimeout, "download-timeout", 5*time.Minute, "Maximum time to spend downloading an http(s) URL input")
+	convertCmd.Flags().StringVar(&opts.DumpCommand, "dump-command", "", "Write the exact FFmpeg command to a file (use - for stdout)")
+	convertCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate and probe the input, show the FFmpeg command and estimated size, but don't convert")
+	convertCmd.Flags().Float64Var(&opts.Segment, "segment", 0, "Split the clip into consecutive GIFs of this many seconds each (out-000.gif, out-001.gif, ...)")
+	convertCmd.Flags().BoolVar(&opts.ByChapters, "by-chapters", false, "Create one GIF per chapter marker, named after the chapter title")
+	convertCmd.Flags().StringVar(&opts.Upload, "upload", "", "Upload the finished GIF and print a shareable link (imgur; needs IMGUR_CLIENT_ID)")
+	convertCmd.Flags().BoolVar(&opts.Clipboard, "clipboard", false, "Copy the output path (or the --upload URL) to the clipboard when done")
+	convertCmd.Flags().BoolVar(&opts.StripMetadata, "strip-metadata", false, "Leave the source's metadata (titles, dates, location) out of the output")
+	convertCmd.Flags().StringVar(&opts.Comment, "comment", "", "Embed a comment in the output, e.g. an attribution (a GIF comment extension, or metadata for WebM and MP4)")
+	convertCmd.Flags().BoolVar(&opts.OptimizeGo, "optimize-go", false, "Shrink the finished GIF by merging duplicate frames, without external tools")
+	convertCmd.Flags().BoolVar(&opts.Analyze, "analyze", false, "Decode the finished GIF and report the colors, palettes and frame structure")
+	convertCmd.Flags().BoolVar(&opts.ForceReencode, "force-reencode", false, "Run GIF inputs through the full palette pipeline instead of only trimming, resizing and reducing their colors")
+	convertCmd.Flags().BoolVar(&opts.Web, "web", false, "Write an MP4 tuned for autoplaying, looping web embeds (H.264, yuv420p, faststart)")
+	convertCmd.Flags().BoolVar(&opts.Poster, "poster", false, "Also save the first frame of the output as a JPEG poster image")
+	convertCmd.Flags().BoolVar(&opts.ContactSheet, "contact-sheet", false, "Output a single PNG grid of evenly-spaced frames instead of a GIF")
+	convertCmd.Flags().IntVar(&opts.Rows, "rows", 3, "Number of rows in the contact sheet")
+	convertCmd.Flags().IntVar(&opts.Cols, "cols", 3, "Number of columns in the contact sheet")
+
+	convertCmd.MarkFlagsMutuallyExclusive("duration", "end")
 
 	// Initialize the FFmpeg manager
 	ffmpegManager = ffmpeg.NewManager()
 
 	rootCmd.AddCommand(convertCmd)
+
+	// The batch command converts with the same options
+	batchCmd.Flags().AddFlagSet(convertCmd.Flags())
 }
 
-// Helper function to open a file explorer dialog
-func openFileDialog(isInput bool) string {
+// Helper function to open a file explorer dialog, starting in startDir if set
+func openFileDialog(isInput bool, startDir string) string {
 	var cmd *exec.Cmd
 	var output []byte
 	var err error
@@ -142,6 +1182,10 @@ func openFileDialog(isInput bool) string {
 			promptText = "Save output GIF as"
 		}
 
+		if startDir != "" {
+			extraParams = fmt.Sprintf(`%s default location POSIX file "%s"`, extraParams, startDir)
+		}
+
 		// Create a temporary AppleScript file with improved default name handling
 		scriptContent := fmt.Sprintf(`
 			set theFile to choose %s with prompt "%s:" %s
@@ -150,7 +1194,7 @@ func openFileDialog(isInput bool) string {
 		`, dialogType, promptText, extraParams)
 
 		// Write script to temporary file
-		tmpFile, err := os.CreateTemp("", "filepicker-*.scpt")
+		tmpFile, err := os.CreateTemp(tempRoot, "filepicker-*.scpt")
 		if err == nil {
 			defer os.Remove(tmpFile.Name())
 			if _, err = tmpFile.WriteString(scriptContent); err == nil {
@@ -162,11 +1206,14 @@ func openFileDialog(isInput bool) string {
 	case "windows":
 		// Windows - use PowerShell to open a file dialog
 		dialogCode := ""
+		// An empty InitialDirectory lets Windows pick its usual default
+		initialDir := startDir
 		if isInput {
 			dialogCode = `[System.Reflection.Assembly]::LoadWithPartialName("System.windows.forms") | Out-Null
 			$OpenFileDialog = New-Object System.Windows.Forms.OpenFileDialog
 			$OpenFileDialog.Title = "Select a video file"
-			$OpenFileDialog.filter = "Video files|*.mp4;*.avi;*.mov;*.mkv;*.webm|All files|*.*"
+			$OpenFileDialog.filter = "Video files|*.mp4;*.avi;*.mov;*.mkv;*.webm;*.gif|All files|*.*"
+			$OpenFileDialog.InitialDirectory = "` + initialDir + `"
 			$OpenFileDialog.ShowDialog() | Out-Null
 			$OpenFileDialog.FileName`
 		} else {
@@ -176,6 +1223,7 @@ func openFileDialog(isInput bool) string {
 			$SaveFileDialog.filter = "GIF files|*.gif|All files|*.*"
 			$SaveFileDialog.DefaultExt = "gif"
 			$SaveFileDialog.FileName = "` + defaultGifName + `"
+			$SaveFileDialog.InitialDirectory = "` + initialDir + `"
 			$SaveFileDialog.ShowDialog() | Out-Null
 			$SaveFileDialog.FileName`
 		}
@@ -190,7 +1238,9 @@ func openFileDialog(isInput bool) string {
 		if !isInput {
 			dialogType = "--file-selection --save"
 			dialogTitle = "Save output GIF as"
-			extraParams = fmt.Sprintf(`--filename="%s"`, defaultGifName)
+			extraParams = fmt.Sprintf(`--filename="%s"`, filepath.Join(startDir, defaultGifName))
+		} else if startDir != "" {
+			extraParams = fmt.Sprintf(`--filename="%s"`, startDir+string(filepath.Separator))
 		}
 
 		args := []string{
@@ -222,6 +1272,9 @@ func openFileDialog(isInput bool) string {
 }
 
 func promptForOptions() error {
+	// Recent choices pre-populate the prompts and file dialogs
+	history := loadHistory()
+
 	// Ask if user wants to use file picker for input
 	var useFilePicker bool
 	pickerQuestion := &survey.Confirm{
@@ -235,7 +1288,7 @@ func promptForOptions() error {
 	// Input file prompt
 	if useFilePicker {
 		fmt.Println("Opening file dialog, please select your input video file...")
-		path := openFileDialog(true)
+		path := openFileDialog(true, history.LastInputDir())
 		if path != "" {
 			opts.Input = path
 			fmt.Printf("Selected file: %s\n", opts.Input)
@@ -244,6 +1297,7 @@ func promptForOptions() error {
 			var inputQuestion = &survey.Input{
 				Message: "Input video file path:",
 				Help:    "Path to the video file you want to convert to a GIF",
+				Default: history.LastInput(),
 			}
 			if err := survey.AskOne(inputQuestion, &opts.Input, survey.WithValidator(survey.Required)); err != nil {
 				return err
@@ -253,29 +1307,46 @@ func promptForOptions() error {
 		var inputQuestion = &survey.Input{
 			Message: "Input video file path:",
 			Help:    "Path to the video file you want to convert to a GIF",
+			Default: history.LastInput(),
 		}
 		if err := survey.AskOne(inputQuestion, &opts.Input, survey.WithValidator(survey.Required)); err != nil {
 			return err
 		}
 	}
 
-	// Check if input file exists
-	if _, err := os.Stat(opts.Input); os.IsNotExist(err) {
-		return fmt.Errorf("input file does not exist: %s", opts.Input)
+	// Typed paths aren't expanded by a shell, so expand ~ and $VARs before
+	// looking at the file
+	opts.Input = expandPath(opts.Input)
+
+	// URLs are downloaded and validated after prompting
+	defaultOutput := filepath.Join(filepath.Dir(opts.Input), defaultOutputPath(opts.Input))
+	if isURL(opts.Input) {
+		defaultOutput = defaultOutputPath(remoteFileName(opts.Input))
+	} else {
+		// Check if input file exists
+		if _, err := os.Stat(opts.Input); os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "input file", Path: opts.Input}
+		}
+
+		// Make sure the input is a decodable video
+		if !isGIFInput(opts.Input) {
+			if err := checkVideoInput(opts.Input); err != nil {
+				return err
+			}
+		}
 	}
 
-	// Validate input file has a valid video extension
-	if !isValidVideoFile(opts.Input) {
-		return fmt.Errorf("input file must be a valid video format (mp4, avi, mov, mkv, webm): %s", opts.Input)
+	// Default the output to the last used output directory
+	if dir := history.LastOutputDir(); dir != "" && !isURL(opts.Input) {
+		defaultOutput = filepath.Join(dir, filepath.Base(defaultOutput))
 	}
 
 	// Output file prompt
-	defaultOutput := strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)) + ".gif"
 
 	// Ask if user wants to use file picker for output
 	if useFilePicker {
 		fmt.Println("Opening file dialog, please choose where to save the output GIF...")
-		path := openFileDialog(false)
+		path := openFileDialog(false, history.LastOutputDir())
 		if path != "" {
 			opts.Output = path
 			// Ensure it has .gif extension
@@ -307,7 +1378,7 @@ func promptForOptions() error {
 
 		if useOutputFilePicker {
 			fmt.Println("Opening file dialog, please choose where to save the output GIF...")
-			path := openFileDialog(false)
+			path := openFileDialog(false, history.LastOutputDir())
 			if path != "" {
 				opts.Output = path
 				// Ensure it has .gif extension
@@ -325,239 +1396,926 @@ func promptForOptions() error {
 					return err
 				}
 			}
-		} else {
-			var outputQuestion = &survey.Input{
-				Message: "Output GIF file path:",
-				Default: defaultOutput,
+		} else {
+			var outputQuestion = &survey.Input{
+				Message: "Output GIF file path:",
+				Default: defaultOutput,
+			}
+			if err := survey.AskOne(outputQuestion, &opts.Output); err != nil {
+				return err
+			}
+		}
+	}
+
+	opts.Output = expandPath(opts.Output)
+
+	// FPS prompt
+	var fpsQuestion = &survey.Input{
+		Message: "Frames per second (higher = smoother but larger file, auto = match source):",
+		Default: "10",
+	}
+	var fpsStr string
+	if err := survey.AskOne(fpsQuestion, &fpsStr); err != nil {
+		return err
+	}
+	if err := setFPS(fpsStr); err != nil {
+		return err
+	}
+
+	// With a known length the clip can be picked as percentages or times
+	if !isURL(opts.Input) {
+		if total := probeDuration(opts.Input); total > 0 {
+			if err := promptClipRange(total); err != nil {
+				return err
+			}
+			return promptOutputSettings(history, total)
+		}
+	}
+
+	// Start time prompt
+	var startQuestion = &survey.Input{
+		Message: "Start time (format: 00:00:00, leave empty for beginning):",
+		Default: "",
+	}
+	if err := survey.AskOne(startQuestion, &opts.Start); err != nil {
+		return err
+	}
+
+	// The clip can end after a duration or at a timestamp
+	var rangeMode string
+	var rangeModeQuestion = &survey.Select{
+		Message: "How do you want to set the end of the clip?",
+		Options: []string{"Duration", "End time"},
+		Default: "Duration",
+	}
+	if err := survey.AskOne(rangeModeQuestion, &rangeMode); err != nil {
+		return err
+	}
+
+	if rangeMode == "End time" {
+		var endQuestion = &survey.Input{
+			Message: "End time (format: 00:00:00, leave empty for end of video):",
+			Default: "",
+		}
+		if err := survey.AskOne(endQuestion, &opts.End); err != nil {
+			return err
+		}
+	} else {
+		var durationQuestion = &survey.Input{
+			Message: "Duration (format: 00:00:00, leave empty for full video):",
+			Default: "",
+		}
+		if err := survey.AskOne(durationQuestion, &opts.Duration); err != nil {
+			return err
+		}
+	}
+
+	return promptOutputSettings(history, 0)
+}
+
+// promptOutputSettings asks for the output size and quality, then saves
+// the interactive history. If the length of the video is known, samples
+// can be made to show what each quality costs.
+func promptOutputSettings(history *History, total float64) error {
+	// Width prompt
+	var widthQuestion = &survey.Input{
+		Message: "Width in pixels (leave empty to keep original size):",
+		Default: "",
+	}
+	var widthStr string
+	if err := survey.AskOne(widthQuestion, &widthStr); err != nil {
+		return err
+	}
+	if widthStr != "" {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil || width < 1 {
+			return fmt.Errorf("invalid width value: %s", widthStr)
+		}
+		opts.Width = width
+	}
+
+	// Quality prompt
+	qualityOptions := make([]string, len(interactiveQualities))
+	for i, level := range interactiveQualities {
+		qualityOptions[i] = level.Label
+	}
+	if total > 0 {
+		if err := promptQualitySamples(total, qualityOptions); err != nil {
+			return err
+		}
+	}
+	var qualityIndex int
+	var qualityQuestion = &survey.Select{
+		Message: "Select quality:",
+		Options: qualityOptions,
+		Default: 1,
+	}
+	if err := survey.AskOne(qualityQuestion, &qualityIndex); err != nil {
+		return err
+	}
+
+	opts.Quality = interactiveQualities[qualityIndex].Quality
+
+	// Remember these choices for next time
+	if !isURL(opts.Input) {
+		history.AddInput(opts.Input)
+	}
+	history.AddOutput(opts.Output)
+	if err := history.Save(); err != nil {
+		GetLogger().Warnf("Could not save interactive history: %v", err)
+	}
+
+	return nil
+}
+
+func convertVideo(ctx context.Context) (*ConversionResult, error) {
+	logger := GetLogger()
+	logger.Infof("Starting conversion: %s -> %s", opts.Input, opts.Output)
+
+	// Cancel on Ctrl+C instead of exiting, so every mode gets to remove its
+	// partial output and temp files and the output lock is released
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// H.264 can't encode odd sizes, so fix them before building filters
+	roundEvenDimensions()
+
+	// FFmpeg only understands a duration, which clipDurationOption works
+	// out from --end
+	if opts.End != "" {
+		duration, err := clipDurationFromEnd(opts.Start, opts.End)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Clip ends at %s, using duration %s", opts.End, formatTimestamp(duration))
+	}
+
+	// Keep a second run from writing the same output at the same time,
+	// creating the output directory first so FFmpeg can write there
+	if !opts.DryRun {
+		if err := ensureOutputDir(opts.Output); err != nil {
+			return nil, err
+		}
+		unlock, err := acquireOutputLock(opts.Output)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	// Check if FFmpeg is installed
+	if err := checkFFmpegInstallation(); err != nil {
+		return nil, err
+	}
+
+	// Get FFmpeg path from the manager
+	ffmpegPath, err := ffmpegManager.GetPath()
+	if err != nil {
+		return nil, &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+	}
+
+	// Make sure the input has a video stream before handing it to FFmpeg.
+	// If ffprobe itself fails, let FFmpeg try and report its own error.
+	videoInfo, err := ProbeVideo(opts.Input)
+	if err != nil {
+		logger.Warnf("Could not probe video streams: %v", err)
+	} else if !videoInfo.HasVideo() {
+		return nil, fmt.Errorf("no video stream found in %s (GIFs are video only; audio-only files can't be converted)", opts.Input)
+	}
+
+	// Settle on the frame rate: auto, then the --min-fps/--max-fps clamps
+	resolveFPS(videoInfo)
+	if opts.Interpolate != "" {
+		warnInterpolate(videoInfo)
+	}
+
+	// Transparency only survives if the source has an alpha channel
+	if opts.PreserveAlpha && videoInfo != nil {
+		if hasAlphaChannel(videoInfo) {
+			opts.decoder = alphaDecoder(videoInfo)
+		} else {
+			warning := fmt.Sprintf("%s has no alpha channel (pixel format %s); --preserve-alpha has no effect", opts.Input, videoInfo.PixelFormat)
+			color.Yellow("⚠️ %s", warning)
+			logger.Warn(warning)
+		}
+	}
+
+	// HDR sources look washed out unless they are tone mapped
+	if videoInfo != nil {
+		warnColorSettings(videoInfo)
+	}
+
+	// Contact sheets take a separate path that skips palette generation
+	if opts.ContactSheet {
+		return createContactSheet(ctx, ffmpegPath)
+	}
+
+	// Warn about hardware acceleration that can't work on this platform
+	if warning := hwaccelPlatformWarning(opts.HWAccel, runtime.GOOS); warning != "" {
+		color.Yellow("⚠️ %s", warning)
+		logger.Warn(warning)
+	}
+
+	// Create progress data
+	progress := &ProgressData{
+		StartTime:      time.Now(),
+		ProcessingRate: 1.0,
+	}
+
+	// Find the total duration from the input file
+	totalDuration, videoDimensions, err := getVideoMetadata(opts.Input, ffmpegPath)
+	if err != nil {
+		logger.Warnf("Could not get video metadata: %v", err)
+	}
+
+	if videoDimensions[0] > 0 && videoDimensions[1] > 0 {
+		progress.Width = videoDimensions[0]
+		progress.Height = videoDimensions[1]
+	}
+
+	// Phone videos are often stored sideways with a rotation tag. FFmpeg
+	// only honors it in some filter setups, so apply it explicitly.
+	if !opts.NoAutorotate && videoInfo != nil {
+		opts.autoRotate = videoInfo.Rotation
+		opts.rotateProbed = true
+		if opts.autoRotate != 0 {
+			logger.Infof("Applying %d° rotation from the source's metadata", opts.autoRotate)
+		}
+	}
+
+	// Quarter turns swap the frame's width and height
+	if rotation := effectiveRotation(); rotation == 90 || rotation == 270 {
+		progress.Width, progress.Height = progress.Height, progress.Width
+	}
+
+	// Fall back to ffprobe if FFmpeg didn't report a duration
+	if totalDuration <= 0 && videoInfo != nil {
+		totalDuration = videoInfo.Duration
+	}
+
+	// Drop static frames at the edges before anything depends on the range
+	if opts.AutoTrim {
+		autoTrim(ffmpegPath, totalDuration)
+	}
+
+	// Joined ranges have to be part of the video
+	if len(opts.timeRanges) > 0 {
+		if err := checkRanges(opts.timeRanges, totalDuration); err != nil {
+			return nil, err
+		}
+	}
+
+	// Track progress against the selected clip rather than the whole video,
+	// and work out how many frames the GIF will have
+	_, clipDuration := resolveClipRange(totalDuration)
+	if len(opts.timeRanges) > 0 {
+		clipDuration = gifmaker.RangesDuration(opts.timeRanges)
+	}
+	if clipDuration > 0 {
+		progress.TotalDuration = clipDuration
+		if opts.FPSMode == gifmaker.FPSModeCFR {
+			progress.TotalFrames = expectedFrameCount(clipDuration, opts.FPS)
+		}
+	}
+
+	// In sample mode, pick every Nth source frame and hold each one
+	if opts.SampleFrames > 0 {
+		sourceFPS := getSourceFrameRate(opts.Input)
+		if clipDuration <= 0 || sourceFPS <= 0 {
+			return nil, fmt.Errorf("could not determine the frame count of %s for --sample-frames", opts.Input)
+		}
+		opts.sampleInterval = sampleFrameInterval(int64(clipDuration*sourceFPS), opts.SampleFrames)
+		logger.Debugf("Sampling %d frames, one every %d source frames", opts.SampleFrames, opts.sampleInterval)
+
+		progress.TotalDuration = float64(opts.SampleFrames) * opts.FrameHold
+		progress.TotalFrames = int64(opts.SampleFrames)
+	}
+
+	// A speed curve changes how long the clip plays for
+	if len(opts.speedCurve) > 0 && clipDuration > 0 {
+		progress.TotalDuration = gifmaker.SpeedCurveDuration(opts.speedCurve, clipDuration)
+		if opts.FPSMode == gifmaker.FPSModeCFR {
+			progress.TotalFrames = expectedFrameCount(progress.TotalDuration, opts.FPS)
+		}
+	}
+
+	// How many frames scene mode keeps isn't known until FFmpeg has seen them
+	if opts.SceneThreshold > 0 || opts.Dedupe {
+		progress.TotalFrames = 0
+	}
+
+	// Held frames are extra output frames that FFmpeg reports progress for
+	if pauses := opts.StartPause + opts.EndPause; pauses > 0 && progress.TotalDuration > 0 {
+		progress.TotalDuration += pauses
+		if progress.TotalFrames > 0 {
+			progress.TotalFrames += pauseFrameCount(opts.StartPause, opts.FPS) + pauseFrameCount(opts.EndPause, opts.FPS)
+		}
+	}
+
+	// A fixed delay changes how long the GIF plays for
+	if opts.Delay > 0 && progress.TotalFrames > 0 {
+		progress.TotalDuration = float64(progress.TotalFrames*int64(opts.Delay)) / 100
+	}
+
+	// Pick how the palette is built from how much the colors change
+	if opts.AutoStatsMode {
+		autoStatsMode(ffmpegPath, totalDuration)
+	}
+
+	// Detect letterboxing before building the filter chain
+	if opts.Autocrop {
+		crop, err := detectCrop(ffmpegPath, opts.Input, opts.Start, gifmaker.TransformFilter(effectiveRotation(), opts.Flip), skipAutorotate(), progress.Width, progress.Height)
+		if err != nil {
+			logger.Warnf("Crop detection failed, not cropping: %v", err)
+		} else if crop == "" {
+			logger.Info("Crop detection was inconclusive, not cropping")
+			fmt.Println("No black bars detected, not cropping")
+		} else {
+			opts.crop = crop
+			logger.Infof("Detected crop: %s", crop)
+			fmt.Printf("Detected black bars, cropping to %s\n", crop)
+		}
+	}
+
+	// Reach the requested aspect ratio on top of any autocrop
+	if opts.Aspect != "" {
+		if err := applyAspect(progress.Width, progress.Height); err != nil {
+			return nil, err
+		}
+		logger.Debugf("Aspect %s (%s): crop %q, pad %q, size %dx%d", opts.Aspect, opts.AspectMode, opts.crop, opts.pad, opts.Width, opts.height)
+	}
+
+	// Long recordings can be split into several fixed-length GIFs
+	if opts.Segment > 0 {
+		return convertSegments(ctx, ffmpegPath, progress, totalDuration)
+	}
+
+	// Or into one GIF per chapter marker
+	if opts.ByChapters {
+		chapters, err := GetChapters(opts.Input)
+		if err != nil {
+			return nil, err
+		}
+		if len(chapters) == 0 {
+			return nil, fmt.Errorf("%s has no chapter markers; use --segment to split it at fixed intervals instead", opts.Input)
+		}
+		logger.Infof("Found %d chapters in %s", len(chapters), opts.Input)
+		return runSegments(ctx, ffmpegPath, progress, chapterSegments(chapters, opts.Output))
+	}
+
+	ffmpegArgs := libraryOptions(ffmpegPath, opts.HWAccel).Args()
+
+	// Save the exact command so it can be reproduced by hand
+	if opts.DumpCommand != "" {
+		if opts.Parallel > 1 {
+			logger.Warn("--dump-command shows the single-pass command; --parallel runs several commands instead")
+		}
+		if err := dumpCommand(opts.DumpCommand, ffmpegPath, ffmpegArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.DryRun {
+		printDryRun(ffmpegPath, ffmpegArgs, progress)
+		return nil, nil
+	}
+
+	startTime := time.Now()
+
+	// Long clips can be split into chunks that are converted concurrently
+	if opts.Parallel > 1 {
+		if err := convertParallel(ctx, ffmpegPath, progress, totalDuration); err != nil {
+			return nil, err
+		}
+		if opts.OptimizeGo {
+			optimizeOutputs(progress)
+		}
+		if err := addOutputComments(conversionOutputs()); err != nil {
+			return nil, err
+		}
+		return newConversionResult(progress, time.Since(startTime))
+	}
+
+	err = runConversion(ctx, ffmpegPath, opts.HWAccel, progress)
+
+	// Hardware decoding only speeds up decode, so fall back to software if it fails
+	if err != nil && ctx.Err() == nil && opts.HWAccel != "" && opts.HWAccel != "none" {
+		logger.Warnf("Conversion with -hwaccel %s failed, retrying with software decoding: %v", opts.HWAccel, err)
+		color.Yellow("⚠️ Hardware acceleration (%s) failed, retrying with software decoding...", opts.HWAccel)
+		err = runConversion(ctx, ffmpegPath, "none", progress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OptimizeGo {
+		optimizeOutputs(progress)
+	}
+	if err := addOutputComments(conversionOutputs()); err != nil {
+		return nil, err
+	}
+	return newConversionResult(progress, time.Since(startTime))
+}
+
+// printConversionSummary prints the summary box for a finished conversion
+func printConversionSummary(result *ConversionResult) {
+	logger := GetLogger()
+	outputs := result.Outputs
+	elapsedTime := result.Elapsed.Seconds()
+
+	// Print summary with richer formatting
+	fmt.Println()
+	if len(outputs) > 1 {
+		color.New(color.FgHiGreen, color.Bold).Printf("✅ %d %ss created successfully!\n", len(outputs), outputKind())
+	} else {
+		color.New(color.FgHiGreen, color.Bold).Printf("✅ %s created successfully!\n", outputKind())
+	}
+
+	// Display detailed information about the conversion
+	fmt.Println()
+	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+	if len(outputs) > 1 {
+		for _, output := range outputs {
+			fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprintf(" %s:", output.Label), fmt.Sprintf("%s (%.2f MB)", output.Path, output.SizeMB()))
+		}
+	} else {
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), outputs[0].Path)
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Size:"), fmt.Sprintf("%.2f MB", outputs[0].SizeMB()))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Dimensions:"), fmt.Sprintf("%dx%d", result.Width, result.Height))
+	}
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Frames:"), fmt.Sprintf("%d frames at %s", result.Frames, formatOutputFPS()))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Conversion time:"), fmt.Sprintf("%.1f seconds", elapsedTime))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Processing rate:"), fmt.Sprintf("%.2fx real-time", result.ProcessRate))
+
+	// Report what actually ended up in the GIF, for tuning --quality
+	if opts.Analyze {
+		for _, output := range outputs {
+			analysis, err := analyzeGIF(output.Path)
+			if err != nil {
+				logger.Warnf("Could not analyze %s: %v", output.Path, err)
+				continue
+			}
+			fmt.Println("├─" + strings.Repeat("─", 50) + "┤")
+			if len(outputs) > 1 {
+				fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Analysis:"), output.Label)
 			}
-			if err := survey.AskOne(outputQuestion, &opts.Output); err != nil {
-				return err
+			for _, row := range analysis.summaryRows() {
+				fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" "+row[0]), row[1])
 			}
 		}
 	}
+	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
 
-	// FPS prompt
-	var fpsQuestion = &survey.Input{
-		Message: "Frames per second (higher = smoother but larger file):",
-		Default: "10",
-	}
-	var fpsStr string
-	if err := survey.AskOne(fpsQuestion, &fpsStr); err != nil {
-		return err
+	for _, output := range outputs {
+		logger.Infof("Conversion completed: %s (%.2f MB) in %.1f seconds",
+			output.Path, output.SizeMB(), elapsedTime)
 	}
-	fps, err := strconv.Atoi(fpsStr)
-	if err != nil || fps < 1 {
-		return fmt.Errorf("invalid FPS value: %s", fpsStr)
+}
+
+// outputKind names the output format for messages
+func outputKind() string {
+	switch opts.Format {
+	case gifmaker.FormatWebM:
+		return "WebM"
+	case gifmaker.FormatMP4:
+		return "MP4"
 	}
-	opts.FPS = fps
+	return "GIF"
+}
 
-	// Start time prompt
-	var startQuestion = &survey.Input{
-		Message: "Start time (format: 00:00:00, leave empty for beginning):",
-		Default: "",
+// conversionOutputs returns the files a conversion writes
+func conversionOutputs() []string {
+	return libraryOptions("", "").Outputs()
+}
+
+// printDryRun shows what a conversion would do without running FFmpeg
+func printDryRun(ffmpegPath string, ffmpegArgs []string, progress *ProgressData) {
+	cyan := color.New(color.FgHiCyan).SprintFunc()
+
+	fmt.Println()
+	color.New(color.FgHiYellow, color.Bold).Println("Dry run: resolved options")
+	fmt.Printf("  %s %s\n", cyan("Input:     "), opts.Input)
+	fmt.Printf("  %s %s\n", cyan("Output:    "), strings.Join(conversionOutputs(), ", "))
+	if opts.Poster {
+		fmt.Printf("  %s %s\n", cyan("Poster:    "), posterPath(opts.Output))
 	}
-	if err := survey.AskOne(startQuestion, &opts.Start); err != nil {
-		return err
+	fmt.Printf("  %s %s\n", cyan("Frame rate:"), formatOutputFPS())
+	if opts.Start != "" {
+		fmt.Printf("  %s %s\n", cyan("Start:     "), opts.Start)
 	}
-
-	// Duration prompt
-	var durationQuestion = &survey.Input{
-		Message: "Duration (format: 00:00:00, leave empty for full video):",
-		Default: "",
+	if opts.Duration != "" {
+		fmt.Printf("  %s %s\n", cyan("Duration:  "), opts.Duration)
 	}
-	if err := survey.AskOne(durationQuestion, &opts.Duration); err != nil {
-		return err
+	if opts.End != "" {
+		fmt.Printf("  %s %s\n", cyan("End:       "), opts.End)
 	}
-
-	// Width prompt
-	var widthQuestion = &survey.Input{
-		Message: "Width in pixels (leave empty to keep original size):",
-		Default: "",
+	if len(opts.timeRanges) > 0 {
+		fmt.Printf("  %s %s\n", cyan("Ranges:    "), formatRanges(opts.timeRanges))
 	}
-	var widthStr string
-	if err := survey.AskOne(widthQuestion, &widthStr); err != nil {
-		return err
+	if progress.TotalDuration > 0 {
+		fmt.Printf("  %s %.2f seconds\n", cyan("Clip length:"), progress.TotalDuration)
 	}
-	if widthStr != "" {
-		width, err := strconv.Atoi(widthStr)
-		if err != nil || width < 1 {
-			return fmt.Errorf("invalid width value: %s", widthStr)
+	if opts.Width > 0 && opts.Height > 0 {
+		fmt.Printf("  %s %dx%d px (%s)\n", cyan("Size:      "), opts.Width, opts.Height, opts.Fit)
+	} else if opts.Width > 0 {
+		fmt.Printf("  %s %d px\n", cyan("Width:     "), opts.Width)
+	} else if opts.Height > 0 {
+		fmt.Printf("  %s %d px\n", cyan("Height:    "), opts.Height)
+	}
+	if opts.crop != "" {
+		fmt.Printf("  %s %s\n", cyan("Crop:      "), opts.crop)
+	}
+	if opts.Format == gifmaker.FormatGIF && opts.PaletteFile == "" {
+		colors, dither := gifmaker.QualityPalette(opts.Quality)
+		if opts.dither != "" {
+			dither = opts.dither
 		}
-		opts.Width = width
+		fmt.Printf("  %s %d (%d colors, dither %s)\n", cyan("Quality:   "), opts.Quality, colors, dither)
+	} else {
+		fmt.Printf("  %s %d\n", cyan("Quality:   "), opts.Quality)
 	}
-
-	// Quality prompt
-	var qualityOptions = []string{"Low (faster, smaller file)", "Medium", "High (slower, larger file)"}
-	var qualityIndex int
-	var qualityQuestion = &survey.Select{
-		Message: "Select quality:",
-		Options: qualityOptions,
-		Default: 1,
+	if progress.Width > 0 && progress.Height > 0 {
+		fmt.Printf("  %s %dx%d\n", cyan("Source:    "), progress.Width, progress.Height)
 	}
-	if err := survey.AskOne(qualityQuestion, &qualityIndex); err != nil {
-		return err
+	if progress.TotalFrames > 0 {
+		fmt.Printf("  %s %d\n", cyan("Frames:    "), progress.TotalFrames)
 	}
 
-	// Map quality selection to actual quality value
-	switch qualityIndex {
-	case 0:
-		opts.Quality = 50
-	case 1:
-		opts.Quality = 75
-	case 2:
-		opts.Quality = 95
+	// Estimate the output size using the same heuristic as the info command
+	if opts.Format == gifmaker.FormatGIF && progress.Width > 0 && progress.Height > 0 && progress.TotalFrames > 0 {
+		widths := opts.Sizes
+		if len(widths) == 0 {
+			widths = []int{opts.Width}
+		}
+		colors, dither := gifmaker.QualityPalette(opts.Quality)
+		for _, w := range widths {
+			outW, outH := progress.Width, progress.Height
+			if w > 0 {
+				outW, outH = w, progress.Height*w/progress.Width
+			}
+			fmt.Printf("  %s ~%s (%dx%d)\n", cyan("Est. size: "), HumanizeBytes(EstimateGIFSize(outW, outH, int(progress.TotalFrames), colors, dither != "none")), outW, outH)
+		}
 	}
 
-	return nil
+	fmt.Println()
+	color.New(color.FgHiYellow, color.Bold).Println("FFmpeg command:")
+	fmt.Println(formatShellCommand(ffmpegPath, ffmpegArgs))
+	fmt.Println()
+	color.Green("Dry run, nothing written.")
 }
 
-func convertVideo() error {
-	logger := GetLogger()
-	logger.Infof("Starting conversion: %s -> %s", opts.Input, opts.Output)
-
-	// Check if FFmpeg is installed
-	if err := checkFFmpegInstallation(); err != nil {
-		return err
+// dumpCommand writes a copy-pasteable FFmpeg command line to dest, or to
+// stdout if dest is "-"
+func dumpCommand(dest, ffmpegPath string, ffmpegArgs []string) error {
+	var b strings.Builder
+	for i := 0; i+1 < len(ffmpegArgs); i++ {
+		if ffmpegArgs[i] == "-filter_complex" || ffmpegArgs[i] == "-vf" {
+			fmt.Fprintf(&b, "# %s: %s\n", strings.TrimPrefix(ffmpegArgs[i], "-"), ffmpegArgs[i+1])
+		}
 	}
+	fmt.Fprintln(&b, formatShellCommand(ffmpegPath, ffmpegArgs))
 
-	// Get FFmpeg path from the manager
-	ffmpegPath, err := ffmpegManager.GetPath()
-	if err != nil {
-		return fmt.Errorf("Failed to get FFmpeg: %w", err)
+	if dest == "-" {
+		fmt.Print(b.String())
+		return nil
 	}
 
-	// Prepare FFmpeg arguments
-	ffmpegArgs := []string{"-i", opts.Input}
-
-	// Add global options for better compatibility
-	ffmpegArgs = append([]string{
-		"-y",
-		"-loglevel", "info",
-		"-threads", fmt.Sprintf("%d", GetOptimalThreads()),
-		"-progress", "pipe:1",
-		"-stats_period", "0.1",
-	}, ffmpegArgs...)
+	if err := os.WriteFile(dest, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write FFmpeg command to %s: %w", dest, err)
+	}
+	fmt.Printf("FFmpeg command written to: %s\n", dest)
+	return nil
+}
 
-	if opts.Start != "" {
-		ffmpegArgs = append(ffmpegArgs, "-ss", opts.Start)
+// formatOutputFPS describes the effective frame rate of the output GIF
+func formatOutputFPS() string {
+	if opts.Delay > 0 {
+		return fmt.Sprintf("%dcs per frame", opts.Delay)
+	}
+	if opts.SampleFrames > 0 {
+		return fmt.Sprintf("%.2f fps (sampled)", 1/opts.FrameHold)
+	}
+	if opts.SceneThreshold > 0 {
+		return fmt.Sprintf("%.2f fps (scene changes)", 1/opts.FrameHold)
+	}
+	if opts.FPSMode != gifmaker.FPSModeCFR {
+		return fmt.Sprintf("source timing (%s)", opts.FPSMode)
+	}
+	var notes []string
+	if opts.fpsAuto {
+		notes = append(notes, "auto")
+	}
+	if opts.Interpolate != "" {
+		if opts.sourceFPS > 0 {
+			notes = append(notes, fmt.Sprintf("%s from %.4g", opts.Interpolate, opts.sourceFPS))
+		} else {
+			notes = append(notes, opts.Interpolate)
+		}
+	}
+	if opts.Dedupe {
+		notes = append(notes, "duplicates dropped")
+	}
+	if opts.fpsClamped > 0 {
+		notes = append(notes, fmt.Sprintf("clamped from %d", opts.fpsClamped))
 	}
+	if len(notes) > 0 {
+		return fmt.Sprintf("%d fps (%s)", opts.FPS, strings.Join(notes, ", "))
+	}
+	return fmt.Sprintf("%d fps", opts.FPS)
+}
 
-	if opts.Duration != "" {
-		ffmpegArgs = append(ffmpegArgs, "-t", opts.Duration)
+// sampleFrameInterval returns how many source frames to skip between
+// samples so that n frames are spread across totalFrames
+func sampleFrameInterval(totalFrames int64, n int) int {
+	if n <= 0 || totalFrames <= int64(n) {
+		return 1
 	}
+	return int(totalFrames / int64(n))
+}
 
-	// Build the filter string
-	filterComplex := fmt.Sprintf("fps=%d", opts.FPS)
+// setFPS parses an --fps value, where "auto" or 0 matches the source
+func setFPS(value string) error {
+	fps, auto, err := parseFPS(value)
+	if err != nil {
+		return err
+	}
+	if auto {
+		opts.fpsAuto = true
+		return nil
+	}
+	opts.FPS = fps
+	opts.fpsAuto = false
+	return nil
+}
 
-	if opts.Width > 0 {
-		filterComplex = fmt.Sprintf("%s,scale=%d:-1:flags=lanczos", filterComplex, opts.Width)
+// parseFPS parses an --fps value into a frame rate, or reports that it
+// asks to match the source
+func parseFPS(value string) (fps int, auto bool, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "auto" || value == "0" {
+		return 0, true, nil
 	}
 
-	// Add the quality parameter (using palettegen for better quality)
-	filterComplex = fmt.Sprintf("%s,split[s0][s1];[s0]palettegen=max_colors=256:stats_mode=diff[p];[s1][p]paletteuse=dither=sierra2_4a:diff_mode=rectangle:alpha_threshold=128", filterComplex)
+	fps, err = strconv.Atoi(value)
+	if err != nil || fps < 1 {
+		return 0, false, usageErrorf("invalid FPS value %q (expected a positive number or auto)", value)
+	}
+	return fps, false, nil
+}
 
-	ffmpegArgs = append(ffmpegArgs, "-filter_complex", filterComplex)
-	ffmpegArgs = append(ffmpegArgs, opts.Output)
+// resolveFPS works out the frame rate to convert at, matching the source for
+// --fps auto and then applying the --min-fps and --max-fps clamps
+func resolveFPS(videoInfo *VideoInfo) {
+	logger := GetLogger()
 
-	// Set up the command using the managed FFmpeg path
-	logger.Debugf("FFmpeg command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
-	if rootCmd.Flag("verbose").Value.String() == "true" {
-		fmt.Printf("Running FFmpeg command: %s %s\n", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	if opts.fpsAuto {
+		var rate float64
+		if videoInfo != nil {
+			rate = videoInfo.FrameRate
+		}
+		opts.FPS = autoFPS(rate)
+		if rate <= 0 {
+			logger.Warnf("Could not determine the source frame rate; using %d fps", opts.FPS)
+		} else if float64(opts.FPS) < math.Round(rate) {
+			logger.Infof("Source runs at %.2f fps; capping at %d fps", rate, opts.FPS)
+		}
 	}
-	ffmpegCmd := exec.Command(ffmpegPath, ffmpegArgs...)
 
-	// Get pipes for stdout and stderr
-	stdout, err := ffmpegCmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	if clamped := clampFPS(opts.FPS, opts.MinFPS, opts.MaxFPS); clamped != opts.FPS {
+		logger.Infof("Clamping %d fps to %d fps", opts.FPS, clamped)
+		opts.fpsClamped = opts.FPS
+		opts.FPS = clamped
 	}
+}
 
-	stderr, err := ffmpegCmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("failed to get stderr pipe: %w", err)
+// clampFPS limits fps to [minFPS, maxFPS], where 0 means no limit on that side
+func clampFPS(fps, minFPS, maxFPS int) int {
+	if minFPS > 0 && fps < minFPS {
+		return minFPS
+	}
+	if maxFPS > 0 && fps > maxFPS {
+		return maxFPS
 	}
+	return fps
+}
 
-	// Create a buffer to capture error output if needed
-	var errOutput strings.Builder
+// autoFPS rounds the source frame rate for --fps auto. GIF delays are whole
+// centiseconds, so anything above maxAutoFPS would just get mangled.
+func autoFPS(sourceRate float64) int {
+	if sourceRate <= 0 {
+		return defaultFPS
+	}
+	fps := int(math.Round(sourceRate))
+	if fps < 1 {
+		return 1
+	}
+	if fps > maxAutoFPS {
+		return maxAutoFPS
+	}
+	return fps
+}
 
-	// Create a reader that both reads to our buffer and can be closed
-	teeStderr := &teeReadCloser{
-		Reader: io.TeeReader(stderr, &errOutput),
-		Closer: stderr,
+// outputHeight returns the output height, which --aspect works out from
+// the width when it's used
+func outputHeight() int {
+	if opts.height > 0 {
+		return opts.height
 	}
+	return opts.Height
+}
 
-	// Create progress data
-	progress := &ProgressData{
-		StartTime:      time.Now(),
-		ProcessingRate: 1.0,
+// outputFit returns how the frame fits the output size. The size --aspect
+// works out already has the right ratio, so it is used exactly.
+func outputFit() string {
+	if opts.height > 0 {
+		return gifmaker.FitStretch
 	}
+	return opts.Fit
+}
 
-	// Find the total duration from the input file
-	totalDuration, videoDimensions, err := getVideoMetadata(opts.Input, ffmpegPath)
+// getSourceFrameRate probes the frame rate of the input video, returning 0
+// if it can't be determined
+func getSourceFrameRate(videoPath string) float64 {
+	video, err := ProbeVideo(videoPath)
 	if err != nil {
-		logger.Warnf("Could not get video metadata: %v", err)
+		GetLogger().Warnf("Could not probe frame rate: %v", err)
+		return 0
 	}
+	return video.FrameRate
+}
 
-	if videoDimensions[0] > 0 && videoDimensions[1] > 0 {
-		progress.Width = videoDimensions[0]
-		progress.Height = videoDimensions[1]
+// libraryOptions translates the CLI options into options for the conversion
+// engine. ffmpegPath and hwaccel are passed separately so a failed hardware
+// accelerated run can be retried in software.
+func libraryOptions(ffmpegPath, hwaccel string) gifmaker.Options {
+	return gifmaker.Options{
+		FFmpegPath:      ffmpegPath,
+		Input:           opts.Input,
+		Output:          opts.Output,
+		FPS:             opts.FPS,
+		FPSMode:         opts.FPSMode,
+		Interpolate:     opts.Interpolate,
+		Delay:           opts.Delay,
+		Start:           opts.Start,
+		Duration:        clipDurationOption(),
+		FastSeek:        opts.FastSeek,
+		Width:           opts.Width,
+		Height:          outputHeight(),
+		Fit:             outputFit(),
+		Quality:         opts.Quality,
+		Format:          opts.Format,
+		Threads:         opts.Threads,
+		Nice:            opts.Nice,
+		HWAccel:         hwaccel,
+		NoOverwrite:     opts.NoOverwrite,
+		PaletteFile:     opts.PaletteFile,
+		SampleFrames:    opts.SampleFrames,
+		SampleInterval:  opts.sampleInterval,
+		FrameHold:       opts.FrameHold,
+		SceneThreshold:  opts.SceneThreshold,
+		Subtitles:       opts.Subtitles,
+		SubtitleStyle:   opts.SubtitleStyle,
+		StatsMode:       opts.statsMode,
+		Dither:          opts.dither,
+		DiffMode:        opts.DiffMode,
+		AlphaCutoff:     opts.AlphaThreshold,
+		SpeedCurve:      opts.speedCurve,
+		Ranges:          opts.timeRanges,
+		Dedupe:          opts.Dedupe,
+		DedupeThreshold: opts.DedupeThreshold,
+		Sizes:           opts.Sizes,
+		EvenDimensions:  opts.ForceEvenDimensions,
+		StripMetadata:   opts.StripMetadata,
+		Comment:         opts.Comment,
+		Rotate:          effectiveRotation(),
+		Flip:            opts.Flip,
+		Crop:            opts.crop,
+		Pad:             opts.pad,
+		ColorRange:      opts.ColorRange,
+		Tonemap:         opts.Tonemap,
+		Denoise:         opts.Denoise,
+		Deband:          opts.Deband,
+		Sharpen:         opts.Sharpen,
+		StartPause:      opts.StartPause,
+		EndPause:        opts.EndPause,
+		PreserveAlpha:   opts.PreserveAlpha,
+		Decoder:         opts.decoder,
+		InputFormat:     opts.InputFormat,
+		InputFrameRate:  opts.InputFrameRate,
+		NoAutorotate:    skipAutorotate(),
+		AccurateSeek:    opts.SeekAccurate,
+
+		Waveform:         opts.Waveform,
+		WaveformPosition: opts.WaveformPosition,
+		WaveformHeight:   opts.WaveformHeight,
+		WaveformColor:    opts.WaveformColor,
 	}
+}
 
-	if totalDuration > 0 {
-		progress.TotalDuration = totalDuration
-	}
+// runConversion runs a single FFmpeg conversion through the gifmaker
+// engine and tracks its progress
+func runConversion(ctx context.Context, ffmpegPath, hwaccel string, progress *ProgressData) error {
+	logger := GetLogger()
 
-	// Start the command
-	startTime := time.Now()
-	if err := ffmpegCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start FFmpeg: %w", err)
+	convOpts := libraryOptions(ffmpegPath, hwaccel)
+	if opts.FFmpegVerbose {
+		convOpts.LogLevel = "verbose"
 	}
-
-	if !opts.NoProgress {
-		// Create and start the progress tracking
-		runMPBProgressTracking(stdout, progress, totalDuration)
-	} else {
-		go trackProgress(teeStderr)
+	ffmpegArgs := convOpts.Args()
+	logger.Debugf("FFmpeg command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	if rootCmd.Flag("verbose").Value.String() == "true" {
+		fmt.Printf("Running FFmpeg command: %s %s\n", ffmpegPath, strings.Join(ffmpegArgs, " "))
 	}
 
-	// Wait for the command to finish
-	if err := ffmpegCmd.Wait(); err != nil {
-		errMsg := errOutput.String()
-		if len(errMsg) > 500 {
-			errMsg = errMsg[len(errMsg)-500:] // Get last 500 chars
+	var onProgress func(gifmaker.Progress)
+	finish := func() {}
+	if opts.FFmpegVerbose {
+		// FFmpeg's own stats line replaces the progress display
+		convOpts.Stderr = os.Stderr
+	} else if opts.ProgressFormat == progressFormatJSON {
+		onProgress = jsonProgressReporter(os.Stderr, progress.TotalDuration)
+	} else if showProgressBars() {
+		onProgress, finish = runMPBProgressTracking(progress, progress.TotalDuration)
+	} else if !opts.NoProgress {
+		onProgress = plainProgressReporter(progress.TotalDuration)
+	} else {
+		onProgress = func(update gifmaker.Progress) {
+			if update.CurrentTime > 0 {
+				fmt.Printf("\r\033[KProgress: %s", formatTime(update.CurrentTime))
+			}
 		}
-		return fmt.Errorf("FFmpeg conversion failed: %w\nLast error output: %s", err, errMsg)
 	}
 
-	elapsedTime := time.Since(startTime).Seconds()
+	result, err := gifmaker.Convert(ctx, convOpts, onProgress)
+	finish()
 
-	// Check the output file
-	fileInfo, err := os.Stat(opts.Output)
+	// The error only has FFmpeg's relevant lines; the full log is opt-in
+	var ffmpegErr *gifmaker.FFmpegError
+	if errors.As(err, &ffmpegErr) && !opts.FFmpegVerbose {
+		if rootCmd.Flag("verbose").Value.String() == "true" {
+			color.New(color.Faint).Printf("\nLast FFmpeg output:\n%s\n", ffmpegErr.Tail(ffmpegLogTail))
+		} else {
+			return fmt.Errorf("%w\n(run with --verbose to see FFmpeg's full output)", err)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get output file info: %w", err)
+		return err
 	}
 
-	fileSizeMB := float64(fileInfo.Size()) / 1024 / 1024
+	progress.Frames = result.Frames
+	progress.AvgProcessRate = result.AvgProcessRate
+	return nil
+}
 
-	// Print summary with richer formatting
-	fmt.Println()
-	color.New(color.FgHiGreen, color.Bold).Println("✅ GIF created successfully!")
+// hwaccelPlatformWarning returns a warning when the requested hardware
+// acceleration method is not available on the given OS
+func hwaccelPlatformWarning(hwaccel, goos string) string {
+	switch hwaccel {
+	case "cuda":
+		if goos == "darwin" {
+			return "CUDA hardware acceleration is not available on macOS"
+		}
+	case "videotoolbox":
+		if goos != "darwin" {
+			return fmt.Sprintf("VideoToolbox hardware acceleration is only available on macOS, not %s", goos)
+		}
+	case "vaapi":
+		if goos != "linux" {
+			return fmt.Sprintf("VAAPI hardware acceleration is only available on Linux, not %s", goos)
+		}
+	}
+	return ""
+}
 
-	// Display detailed information about the conversion
-	fmt.Println()
-	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), opts.Output)
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Size:"), fmt.Sprintf("%.2f MB", fileSizeMB))
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Dimensions:"), fmt.Sprintf("%dx%d", progress.Width, progress.Height))
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Frames:"), fmt.Sprintf("%d frames at %d fps", progress.Frames, opts.FPS))
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Conversion time:"), fmt.Sprintf("%.1f seconds", elapsedTime))
-	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Processing rate:"), fmt.Sprintf("%.2fx real-time", progress.AvgProcessRate))
-	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+// resolveClipRange returns the start and length in seconds of the part of
+// the video selected by --start and --duration or --end
+func resolveClipRange(totalDuration float64) (float64, float64) {
+	// The times are validated before converting, so errors can't happen here
+	startSeconds, _ := gifmaker.TimeToSeconds(opts.Start)
+	clipDuration := totalDuration - startSeconds
+	if duration := clipDurationOption(); duration != "" {
+		if d, err := gifmaker.TimeToSeconds(duration); err == nil && d > 0 && (clipDuration <= 0 || d < clipDuration) {
+			clipDuration = d
+		}
+	}
+	return startSeconds, clipDuration
+}
 
-	logger.Infof("Conversion completed: %s (%.2f MB) in %.1f seconds",
-		opts.Output, fileSizeMB, elapsedTime)
+// expectedFrameCount returns the number of frames a clip of the given
+// length produces at the target frame rate, or 0 if it can't be known
+func expectedFrameCount(clipDuration float64, fps int) int64 {
+	if clipDuration <= 0 || fps <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(clipDuration * float64(fps)))
+}
 
-	return nil
+// pauseFrameCount returns how many cloned frames tpad adds for a pause
+func pauseFrameCount(pause float64, fps int) int64 {
+	if pause <= 0 || fps <= 0 {
+		return 0
+	}
+	return int64(math.Round(pause * float64(fps)))
 }
 
 // Get video metadata (duration and dimensions) using FFmpeg
@@ -597,11 +2355,13 @@ func getVideoMetadata(videoPath, ffmpegPath string) (float64, [2]int, error) {
 	return duration, dimensions, nil
 }
 
-// New progress tracking function using MPB
-func runMPBProgressTracking(r io.ReadCloser, progress *ProgressData, totalDuration float64) {
+// runMPBProgressTracking sets up the MPB progress bars and returns a
+// progress callback for the conversion plus a function that completes the
+// bars once it has finished
+func runMPBProgressTracking(progress *ProgressData, totalDuration float64) (func(gifmaker.Progress), func()) {
 	// Create a new MPB progress container
 	p := mpb.New(
-		mpb.WithWidth(80),
+		mpb.WithWidth(progressBarWidth()),
 		mpb.WithRefreshRate(100*time.Millisecond),
 	)
 
@@ -623,6 +2383,10 @@ func runMPBProgressTracking(r io.ReadCloser, progress *ProgressData, totalDurati
 			decor.Elapsed(decor.ET_STYLE_GO, decor.WCSyncWidth),
 			decor.Name(" • ", decor.WCSyncWidthR),
 			decor.AverageSpeed(0, "%.1fx", decor.WCSyncWidth),
+			decor.Name(" • ", decor.WCSyncWidthR),
+			decor.Any(func(statistics decor.Statistics) string {
+				return formatRemaining(progress.CurrentTime, progress.TotalDuration, progress.SmoothedRate)
+			}, decor.WCSyncWidth),
 		),
 	)
 
@@ -647,147 +2411,92 @@ func runMPBProgressTracking(r io.ReadCloser, progress *ProgressData, totalDurati
 		mpb.BarFillerClearOnComplete(),
 		mpb.PrependDecorators(
 			decor.Any(func(statistics decor.Statistics) string {
+				if progress.TotalFrames > 0 {
+					return fmt.Sprintf("Frames: %d / %d", progress.FramesProcessed, progress.TotalFrames)
+				}
 				return fmt.Sprintf("Frames: %d processed", progress.FramesProcessed)
 			}, decor.WCSyncSpaceR),
 		),
 	)
 
-	// Start a goroutine to parse FFmpeg output
-	go func() {
-		defer r.Close()
-
-		// Track average processing rate
-		var speedSum float64
-		var speedCount int
-
-		// Create a scanner with a larger buffer for FFmpeg output
-		scanner := bufio.NewScanner(r)
-		buf := make([]byte, 64*1024)
-		scanner.Buffer(buf, 1024*1024)
-
-		// Progress format patterns
-		timeRegex := regexp.MustCompile(`time=(\d{2}:\d{2}:\d{2}\.\d{2})`)
-		outTimeRegex := regexp.MustCompile(`out_time=(\d{2}:\d{2}:\d{2}\.\d{2})`)
-		outTimeSecondsRegex := regexp.MustCompile(`out_time_ms=(\d+)`)
-		durationRegex := regexp.MustCompile(`Duration: (\d{2}:\d{2}:\d{2}\.\d{2})`)
-		totalDurationSecondsRegex := regexp.MustCompile(`duration=(\d+\.\d+)`)
-		speedRegex := regexp.MustCompile(`speed=(\d+\.\d+)x`)
-		sizeRegex := regexp.MustCompile(`size=\s*(\d+)(\w+)`)
-		frameRegex := regexp.MustCompile(`frame=\s*(\d+)`)
-		dimensionRegex := regexp.MustCompile(`(\d+)x(\d+)`)
-
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			// Track current time
-			if matches := timeRegex.FindStringSubmatch(line); matches != nil {
-				timeStr := matches[1]
-				newTime := timeToSeconds(timeStr)
-				if newTime > 0 {
-					progress.CurrentTime = newTime
-					// Update the progress bar
-					if totalDuration > 0 {
-						bar.SetCurrent(int64(newTime * 100))
-					} else {
-						// If we don't know the total duration, just increment
-						bar.IncrInt64(1)
-					}
-				}
-			} else if matches := outTimeRegex.FindStringSubmatch(line); matches != nil {
-				timeStr := matches[1]
-				newTime := timeToSeconds(timeStr)
-				if newTime > 0 {
-					progress.CurrentTime = newTime
-					if totalDuration > 0 {
-						bar.SetCurrent(int64(newTime * 100))
-					} else {
-						bar.IncrInt64(1)
-					}
-				}
-			} else if matches := outTimeSecondsRegex.FindStringSubmatch(line); matches != nil {
-				ms, err := strconv.ParseInt(matches[1], 10, 64)
-				if err == nil && ms > 0 {
-					progress.CurrentTime = float64(ms) / 1000000.0
-					if totalDuration > 0 {
-						bar.SetCurrent(int64(progress.CurrentTime * 100))
-					} else {
-						bar.IncrInt64(1)
-					}
-				}
+	// Apply each progress report from the conversion to the bars
+	var prev gifmaker.Progress
+	onProgress := func(update gifmaker.Progress) {
+		// Track current time
+		if update.CurrentTime != prev.CurrentTime && update.CurrentTime > 0 {
+			progress.CurrentTime = update.CurrentTime
+			if totalDuration > 0 {
+				bar.SetCurrent(int64(math.Min(update.CurrentTime, totalDuration) * 100))
+			} else {
+				// If we don't know the total duration, just increment
+				bar.IncrInt64(1)
 			}
+		}
 
-			// Get the duration if we don't have it yet
-			if progress.TotalDuration == 0 {
-				if matches := durationRegex.FindStringSubmatch(line); matches != nil {
-					durationStr := matches[1]
-					progress.TotalDuration = timeToSeconds(durationStr)
-					if progress.TotalDuration > 0 {
-						bar.SetTotal(int64(progress.TotalDuration*100), false)
-					}
-				} else if matches := totalDurationSecondsRegex.FindStringSubmatch(line); matches != nil {
-					duration, err := strconv.ParseFloat(matches[1], 64)
-					if err == nil && duration > 0 {
-						progress.TotalDuration = duration
-						if progress.TotalDuration > 0 {
-							bar.SetTotal(int64(progress.TotalDuration*100), false)
-						}
-					}
-				}
-			}
+		// Get the duration if we don't have it yet
+		if progress.TotalDuration == 0 && update.TotalDuration > 0 {
+			progress.TotalDuration = update.TotalDuration
+			bar.SetTotal(int64(progress.TotalDuration*100), false)
+		}
 
-			// Track encoding speed
-			if matches := speedRegex.FindStringSubmatch(line); matches != nil {
-				s, err := strconv.ParseFloat(matches[1], 64)
-				if err == nil && s > 0 {
-					progress.ProcessingRate = s
+		// Track encoding speed
+		if update.ProcessingRate != prev.ProcessingRate && update.ProcessingRate > 0 {
+			progress.ProcessingRate = update.ProcessingRate
+			progress.SmoothedRate = smoothRate(progress.SmoothedRate, update.ProcessingRate)
+		}
 
-					// Track for final summary
-					speedSum += s
-					speedCount++
-					progress.AvgProcessRate = speedSum / float64(speedCount)
-				}
-			}
+		// Track current file size
+		if update.CurrentSize != prev.CurrentSize && update.CurrentSize > 0 {
+			progress.CurrentSize = update.CurrentSize
+			progress.SizeUnit = update.SizeUnit
+			statusBar.SetTotal(update.CurrentSize+1, false)
+			statusBar.SetCurrent(update.CurrentSize)
+		}
 
-			// Track current file size
-			if matches := sizeRegex.FindStringSubmatch(line); matches != nil {
-				s, err := strconv.ParseInt(matches[1], 10, 64)
-				if err == nil && s > 0 {
-					progress.CurrentSize = s
-					progress.SizeUnit = matches[2]
-					statusBar.SetTotal(int64(s+1), false)
-					statusBar.SetCurrent(int64(s))
-				}
+		// Track frames processed
+		if update.FramesProcessed != prev.FramesProcessed && update.FramesProcessed > 0 {
+			f := update.FramesProcessed
+			progress.FramesProcessed = f
+			progress.Frames = int(f)
+			frameBar.SetTotal(f+1, false)
+			frameBar.SetCurrent(f)
+
+			// Until FFmpeg reports out_time, estimate progress from frames
+			if progress.CurrentTime == 0 && progress.TotalFrames > 0 && totalDuration > 0 {
+				fraction := math.Min(float64(f)/float64(progress.TotalFrames), 1)
+				bar.SetCurrent(int64(fraction * totalDuration * 100))
 			}
+		}
 
-			// Track frames processed
-			if matches := frameRegex.FindStringSubmatch(line); matches != nil {
-				f, err := strconv.ParseInt(matches[1], 10, 64)
-				if err == nil && f > 0 {
-					progress.FramesProcessed = f
-					progress.Frames = int(f)
-					frameBar.SetTotal(int64(f+1), false)
-					frameBar.SetCurrent(int64(f))
-				}
-			}
+		// Track dimensions
+		if update.Width > 0 && update.Height > 0 {
+			progress.Width = update.Width
+			progress.Height = update.Height
+		}
 
-			// Track dimensions
-			if matches := dimensionRegex.FindStringSubmatch(line); matches != nil {
-				w, err1 := strconv.Atoi(matches[1])
-				h, err2 := strconv.Atoi(matches[2])
-				if err1 == nil && err2 == nil && w > 0 && h > 0 {
-					progress.Width = w
-					progress.Height = h
-				}
+		// FFmpeg signals the final block with progress=end
+		if update.Done && !prev.Done {
+			if totalDuration > 0 {
+				bar.SetTotal(total, true)
+			} else {
+				bar.SetTotal(-1, true)
 			}
 		}
 
-		// Make sure the bars are completed when done
-		if bar.Current() < 100 {
+		prev = update
+	}
+
+	// Make sure the bars are completed when done
+	finish := func() {
+		if !bar.Completed() {
 			bar.SetTotal(bar.Current(), true)
 		}
 		statusBar.SetTotal(statusBar.Current(), true)
 		frameBar.SetTotal(frameBar.Current(), true)
-	}()
+		p.Wait()
+	}
+
+	return onProgress, finish
 }
 
 // Update the checkFFmpegInstallation function to use the manager
@@ -797,14 +2506,31 @@ func checkFFmpegInstallation() error {
 	// Get FFmpeg path from the manager
 	ffmpegPath, err := ffmpegManager.GetPath()
 	if err != nil {
-		return fmt.Errorf("FFmpeg not found. Error: %w", err)
+		return &ffmpegUnavailableError{fmt.Errorf("FFmpeg not found. Error: %w", err)}
 	}
 
 	// Test the FFmpeg binary
 	cmd := exec.Command(ffmpegPath, "-version")
 	output, err := cmd.Output()
+
+	// An exit error means FFmpeg ran; anything else means it couldn't be
+	// executed at all, which usually means the temp dir is mounted noexec
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		logger.Warnf("Could not run FFmpeg at %s: %v", ffmpegPath, err)
+
+		systemPath, sysErr := ffmpegManager.FallbackToSystem()
+		if sysErr != nil || systemPath == ffmpegPath {
+			return &ffmpegUnavailableError{fmt.Errorf("FFmpeg at %s could not be executed: %w\n"+
+				"If your temp directory is mounted noexec, install FFmpeg on your PATH or pass --extract-dir with an executable directory", ffmpegPath, err)}
+		}
+
+		logger.Infof("Falling back to system FFmpeg at %s", systemPath)
+		ffmpegPath = systemPath
+		output, err = exec.Command(ffmpegPath, "-version").Output()
+	}
 	if err != nil {
-		return fmt.Errorf("FFmpeg not working properly. Error: %w", err)
+		return &ffmpegUnavailableError{fmt.Errorf("FFmpeg not working properly. Error: %w", err)}
 	}
 
 	// Log FFmpeg version
@@ -814,26 +2540,13 @@ func checkFFmpegInstallation() error {
 	return nil
 }
 
-// ProgressUpdate represents a progress update sent through the channel
-type ProgressUpdate struct {
-	CurrentTime     float64
-	TotalDuration   float64
-	ProcessingRate  float64 // Speed relative to real-time playback
-	CurrentSize     int64
-	SizeUnit        string
-	Bitrate         float64
-	BitrateUnit     string
-	FramesProcessed int64
-	Width           int
-	Height          int
-}
-
 // ProgressData tracks the current state of the conversion
 type ProgressData struct {
 	StartTime       time.Time
 	CurrentTime     float64
 	TotalDuration   float64
 	ProcessingRate  float64 // Ratio of processing speed to real-time
+	SmoothedRate    float64 // ProcessingRate smoothed over recent updates, for the ETA
 	CurrentSize     int64
 	SizeUnit        string
 	Bitrate         float64
@@ -843,20 +2556,7 @@ type ProgressData struct {
 	Height          int
 	AvgProcessRate  float64 // Average processing rate relative to real-time
 	Frames          int
-}
-
-func trackProgress(r io.ReadCloser) {
-	defer r.Close()
-
-	scanner := bufio.NewScanner(r)
-	timeRegex := regexp.MustCompile(`time=(\d{2}:\d{2}:\d{2}\.\d{2})`)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if matches := timeRegex.FindStringSubmatch(line); matches != nil {
-			fmt.Printf("\r\033[KProgress: %s", matches[1])
-		}
-	}
+	TotalFrames     int64 // Expected number of output frames, 0 if unknown
 }
 
 // Helper function to format time in HH:MM:SS format
@@ -867,6 +2567,51 @@ func formatTime(seconds float64) string {
 	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, secs)
 }
 
+// clipDurationFromEnd returns the length in seconds of the clip between
+// start and end
+func clipDurationFromEnd(start, end string) (float64, error) {
+	startSeconds, err := gifmaker.TimeToSeconds(start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time: %w", err)
+	}
+	endSeconds, err := gifmaker.TimeToSeconds(end)
+	if err != nil {
+		return 0, fmt.Errorf("invalid end time: %w", err)
+	}
+	if endSeconds <= startSeconds {
+		return 0, fmt.Errorf("end time %s must be after start time %s", end, startOrZero(start))
+	}
+	return endSeconds - startSeconds, nil
+}
+
+// clipDurationOption returns the clip length to hand FFmpeg: --duration,
+// or the one --end implies. Empty means up to the end of the video.
+func clipDurationOption() string {
+	if opts.End == "" {
+		return opts.Duration
+	}
+	// --end is validated before converting, so errors can't happen here
+	duration, err := clipDurationFromEnd(opts.Start, opts.End)
+	if err != nil {
+		return opts.Duration
+	}
+	return formatTimestamp(duration)
+}
+
+// formatTimestamp formats seconds as HH:MM:SS.mmm for use as a time option
+func formatTimestamp(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// startOrZero returns the start time for messages, treating empty as 00:00:00
+func startOrZero(start string) string {
+	if start == "" {
+		return "00:00:00"
+	}
+	return start
+}
+
 // Helper function to format duration in a human-readable format
 func formatDuration(seconds float64) string {
 	if seconds < 60 {
@@ -878,64 +2623,41 @@ func formatDuration(seconds float64) string {
 	}
 }
 
-// Helper function to format file size
+// formatSize formats a size reported by FFmpeg in whatever unit it used,
+// normalizing it to bytes first so every spelling prints the same way
 func formatSize(size int64, unit string) string {
-	if size == 0 {
-		return "0 KB"
+	bytes := sizeInBytes(size, unit)
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d bytes", bytes)
+	case bytes < 1024*1024:
+		return fmt.Sprintf("%.2f KB", float64(bytes)/1024)
+	case bytes < 1024*1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(bytes)/(1024*1024))
+	default:
+		return fmt.Sprintf("%.2f GB", float64(bytes)/(1024*1024*1024))
 	}
+}
 
-	// Convert to appropriate unit
+// sizeInBytes converts a size reported by FFmpeg to bytes. Newer builds
+// spell binary units (KiB, MiB, GiB); kB, MB and GB are decimal.
+func sizeInBytes(size int64, unit string) int64 {
 	switch strings.ToLower(unit) {
+	case "kib":
+		return size << 10
+	case "mib":
+		return size << 20
+	case "gib":
+		return size << 30
 	case "kb", "k":
-		return fmt.Sprintf("%.2f KB", float64(size))
+		return size * 1000
 	case "mb", "m":
-		return fmt.Sprintf("%.2f MB", float64(size))
+		return size * 1000 * 1000
 	case "gb", "g":
-		return fmt.Sprintf("%.2f GB", float64(size))
-	case "b":
-		if size < 1024 {
-			return fmt.Sprintf("%d bytes", size)
-		} else if size < 1024*1024 {
-			return fmt.Sprintf("%.2f KB", float64(size)/1024)
-		} else {
-			return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
-		}
+		return size * 1000 * 1000 * 1000
 	default:
-		// If the unit is not recognized, try to determine appropriate unit
-		if size < 1024 {
-			return fmt.Sprintf("%d bytes", size)
-		} else if size < 1024*1024 {
-			return fmt.Sprintf("%.2f KB", float64(size)/1024)
-		} else if size < 1024*1024*1024 {
-			return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
-		} else {
-			return fmt.Sprintf("%.2f GB", float64(size)/(1024*1024*1024))
-		}
-	}
-}
-
-// Convert time string in format HH:MM:SS.MS to seconds
-func timeToSeconds(timeStr string) float64 {
-	var h, m, s, ms float64
-	parts := strings.Split(timeStr, ":")
-	if len(parts) == 3 {
-		fmt.Sscanf(parts[0], "%f", &h)
-		fmt.Sscanf(parts[1], "%f", &m)
-		secParts := strings.Split(parts[2], ".")
-		fmt.Sscanf(secParts[0], "%f", &s)
-		if len(secParts) > 1 {
-			msStr := secParts[1]
-			fmt.Sscanf(msStr, "%f", &ms)
-			ms = ms / math.Pow10(len(msStr))
-		}
+		return size
 	}
-	return h*3600 + m*60 + s + ms
-}
-
-// teeReadCloser combines a Reader and Closer to implement ReadCloser
-type teeReadCloser struct {
-	io.Reader
-	io.Closer
 }
 
 // Helper function to format dimensions
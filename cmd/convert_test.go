@@ -0,0 +1,85 @@
+// cmd/convert_test.go
+package cmd
+
+import "testing"
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		unit string
+		want string
+	}{
+		{0, "kB", "0 bytes"},
+		{900, "b", "900 bytes"},
+		{2048, "b", "2.00 KB"},
+		{2, "KiB", "2.00 KB"},
+		{1048576, "KiB", "1.00 GB"},
+		{1000, "kB", "976.56 KB"},
+		{1024, "kB", "1000.00 KB"},
+		{5, "MiB", "5.00 MB"},
+		{5, "MB", "4.77 MB"},
+		{2, "GiB", "2.00 GB"},
+		{2, "GB", "1.86 GB"},
+		{1536, "unknown", "1.50 KB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.size, tt.unit); got != tt.want {
+			t.Errorf("formatSize(%d, %q) = %q, want %q", tt.size, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestSizeInBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		unit string
+		want int64
+	}{
+		{512, "b", 512},
+		{512, "B", 512},
+		{512, "", 512},
+		{3, "kB", 3000},
+		{3, "KB", 3000},
+		{3, "k", 3000},
+		{3, "KiB", 3 << 10},
+		{3, "kib", 3 << 10},
+		{3, "MB", 3000000},
+		{3, "mB", 3000000},
+		{3, "MiB", 3 << 20},
+		{3, "GB", 3000000000},
+		{3, "GiB", 3 << 30},
+	}
+	for _, tt := range tests {
+		if got := sizeInBytes(tt.size, tt.unit); got != tt.want {
+			t.Errorf("sizeInBytes(%d, %q) = %d, want %d", tt.size, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestClipDurationOption(t *testing.T) {
+	saved := opts
+	t.Cleanup(func() { opts = saved })
+
+	tests := []struct {
+		start, duration, end string
+		want                 string
+	}{
+		{"", "", "", ""},
+		{"10", "5", "", "5"},
+		{"", "", "00:00:30", "00:00:30.000"},
+		{"00:01:00", "", "00:01:12.5", "00:00:12.500"},
+	}
+	for _, tt := range tests {
+		opts.Start, opts.Duration, opts.End = tt.start, tt.duration, tt.end
+		if got := clipDurationOption(); got != tt.want {
+			t.Errorf("start %q, duration %q, end %q: clipDurationOption() = %q, want %q", tt.start, tt.duration, tt.end, got, tt.want)
+		}
+		if got := libraryOptions("ffmpeg", "").Duration; got != tt.want {
+			t.Errorf("start %q, duration %q, end %q: libraryOptions().Duration = %q, want %q", tt.start, tt.duration, tt.end, got, tt.want)
+		}
+		// The user's options stay as they gave them
+		if opts.Duration != tt.duration || opts.End != tt.end {
+			t.Errorf("options changed to duration %q, end %q", opts.Duration, opts.End)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// cmd/crop.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// How many seconds of video cropdetect samples
+const cropDetectSeconds = 5
+
+// cropDetectRegex matches the crop suggestion cropdetect writes to stderr
+var cropDetectRegex = regexp.MustCompile(`crop=(\d+):(\d+):(\d+):(\d+)`)
+
+// parseCropDetect returns the most common crop=W:H:X:Y value suggested in
+// FFmpeg's cropdetect output, or "" if there were no suggestions
+func parseCropDetect(output string) string {
+	counts := make(map[string]int)
+	var best string
+	for _, matches := range cropDetectRegex.FindAllStringSubmatch(output, -1) {
+		w, _ := strconv.Atoi(matches[1])
+		h, _ := strconv.Atoi(matches[2])
+		if w <= 0 || h <= 0 {
+			continue
+		}
+
+		value := strings.TrimPrefix(matches[0], "crop=")
+		counts[value]++
+		if best == "" || counts[value] > counts[best] {
+			best = value
+		}
+	}
+	return best
+}
+
+// detectCrop samples the start of the clip with cropdetect and returns the
+// suggested W:H:X:Y crop. It returns "" if detection was inconclusive or no
+// cropping is needed. The transform filter, if any, runs first so the crop
+// matches the rotated frame.
+func detectCrop(ffmpegPath, input, start, transform string, noAutorotate bool, sourceWidth, sourceHeight int) (string, error) {
+	logger := GetLogger()
+
+	// The transform already includes any rotation from the metadata
+	ffmpegArgs := []string{"-hide_banner"}
+	if noAutorotate {
+		ffmpegArgs = append(ffmpegArgs, "-noautorotate")
+	}
+	if start != "" {
+		ffmpegArgs = append(ffmpegArgs, "-ss", start)
+	}
+	filter := "cropdetect"
+	if transform != "" {
+		filter = transform + "," + filter
+	}
+
+	ffmpegArgs = append(ffmpegArgs,
+		"-i", input,
+		"-t", strconv.Itoa(cropDetectSeconds),
+		"-vf", filter,
+		"-f", "null",
+		"-",
+	)
+
+	logger.Debugf("FFmpeg cropdetect command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	output, err := exec.Command(ffmpegPath, ffmpegArgs...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("cropdetect failed: %w", err)
+	}
+
+	crop := parseCropDetect(string(output))
+	if crop == "" {
+		return "", nil
+	}
+
+	// A crop covering the whole frame means there are no bars to remove
+	if sourceWidth > 0 && sourceHeight > 0 && crop == fmt.Sprintf("%d:%d:0:0", sourceWidth, sourceHeight) {
+		return "", nil
+	}
+
+	return crop, nil
+}
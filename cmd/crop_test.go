@@ -0,0 +1,58 @@
+// cmd/crop_test.go
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// cropDetectLine formats a line the way FFmpeg's cropdetect logs it
+func cropDetectLine(crop string) string {
+	return "[Parsed_cropdetect_0 @ 0x55d5c1e0a8c0] x1:0 x2:1919 y1:140 y2:939 w:1920 h:800 x:0 y:140 pts:1 t:0.040000 limit:0.094118 crop=" + crop
+}
+
+func TestParseCropDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"single suggestion", []string{cropDetectLine("1920:800:0:140")}, "1920:800:0:140"},
+		{
+			"most common suggestion wins",
+			[]string{
+				cropDetectLine("1920:1072:0:4"),
+				cropDetectLine("1920:800:0:140"),
+				cropDetectLine("1920:800:0:140"),
+			},
+			"1920:800:0:140",
+		},
+		{
+			"first suggestion wins a tie",
+			[]string{cropDetectLine("1920:800:0:140"), cropDetectLine("1920:816:0:132")},
+			"1920:800:0:140",
+		},
+		{
+			"mixed with other output",
+			[]string{
+				"Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'in.mp4':",
+				"  Duration: 00:00:10.00, start: 0.000000, bitrate: 1205 kb/s",
+				cropDetectLine("1280:536:0:92"),
+				"frame=  125 fps=0.0 q=-0.0 Lsize=N/A time=00:00:05.00 bitrate=N/A speed=  10x",
+			},
+			"1280:536:0:92",
+		},
+		{"zero size is skipped", []string{cropDetectLine("0:0:0:0"), cropDetectLine("1920:800:0:140")}, "1920:800:0:140"},
+		{"only zero sizes", []string{cropDetectLine("0:800:0:0"), cropDetectLine("1920:0:0:0")}, ""},
+		{"negative values don't match", []string{cropDetectLine("-16:-16:8:8")}, ""},
+		{"no suggestions", []string{"  Duration: 00:00:10.00, start: 0.000000, bitrate: 1205 kb/s"}, ""},
+		{"empty output", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCropDetect(strings.Join(tt.lines, "\n")); got != tt.want {
+				t.Errorf("parseCropDetect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// cmd/download.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/vbauerster/mpb/v7"
+	"github.com/vbauerster/mpb/v7/decor"
+)
+
+// isURL reports whether the input is an http or https URL
+func isURL(input string) bool {
+	lower := strings.ToLower(input)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// remoteFileName returns the last path element of a URL, ignoring any query
+func remoteFileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+		return "download"
+	}
+	return path.Base(u.Path)
+}
+
+// downloadInput downloads a video URL to a temp file and returns its path.
+// The caller is responsible for removing the file.
+func downloadInput(rawURL string, timeout time.Duration) (string, error) {
+	logger := GetLogger()
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid input URL: %w", err)
+	}
+
+	// The default client follows redirects
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: server returned %s", rawURL, resp.Status)
+	}
+
+	// Keep the extension so the usual video format checks still apply
+	ext := path.Ext(remoteFileName(resp.Request.URL.String()))
+	tmpFile, err := os.CreateTemp(tempRoot, "gif-maker-download-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for download: %w", err)
+	}
+	defer tmpFile.Close()
+
+	logger.Infof("Downloading %s to %s", rawURL, tmpFile.Name())
+
+	body := io.Reader(resp.Body)
+	var p *mpb.Progress
+	var bar *mpb.Bar
+	if showProgressBars() {
+		p = mpb.New(
+			mpb.WithWidth(progressBarWidth()),
+			mpb.WithRefreshRate(100*time.Millisecond),
+		)
+		bar = p.AddBar(resp.ContentLength,
+			mpb.PrependDecorators(
+				decor.Name("Downloading: ", decor.WC{W: 13, C: decor.DidentRight}),
+				decor.CountersKibiByte("% .1f / % .1f"),
+			),
+			mpb.AppendDecorators(
+				decor.Percentage(decor.WC{W: 5}),
+				decor.Name(" • "),
+				decor.AverageSpeed(decor.UnitKiB, "% .1f"),
+			),
+		)
+		body = bar.ProxyReader(resp.Body)
+	} else {
+		fmt.Printf("Downloading %s...\n", rawURL)
+	}
+
+	_, copyErr := io.Copy(tmpFile, body)
+	if bar != nil {
+		// Content length may be unknown, so complete the bar explicitly
+		bar.SetTotal(-1, true)
+		p.Wait()
+	}
+	if copyErr != nil {
+		removeTemp(tmpFile.Name())
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, copyErr)
+	}
+
+	return tmpFile.Name(), nil
+}
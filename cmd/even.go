@@ -0,0 +1,46 @@
+// cmd/even.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// evenDimensions reports whether the output has to have an even width and
+// height: when asked to, and always for H.264 in yuv420p
+func evenDimensions() bool {
+	return opts.ForceEvenDimensions || opts.Format == gifmaker.FormatMP4
+}
+
+// roundEvenDimensions rounds odd --width, --height and --sizes values to
+// even numbers when the output needs them, warning about each one, rather
+// than leaving FFmpeg to fail or round behind the user's back
+func roundEvenDimensions() {
+	if !evenDimensions() {
+		return
+	}
+
+	logger := GetLogger()
+	round := func(flag string, value int) int {
+		if value <= 0 || value%2 == 0 {
+			return value
+		}
+		even := value - 1
+		if even == 0 {
+			even = 2
+		}
+		warning := fmt.Sprintf("%s %d is odd; using %d so the output has even dimensions", flag, value, even)
+		color.Yellow("⚠️ %s", warning)
+		logger.Warn(warning)
+		return even
+	}
+
+	opts.Width = round("--width", opts.Width)
+	opts.Height = round("--height", opts.Height)
+	for i, w := range opts.Sizes {
+		opts.Sizes[i] = round("--sizes", w)
+	}
+}
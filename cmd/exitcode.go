@@ -0,0 +1,94 @@
+// cmd/exitcode.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Exit codes, so scripts can tell bad input from a broken FFmpeg
+const (
+	exitOK                = 0
+	exitError             = 1 // Anything not covered below
+	exitUsage             = 2 // Invalid flags, arguments or option combinations
+	exitInputNotFound     = 3
+	exitFFmpegUnavailable = 4 // FFmpeg can't be found or run
+	exitConversionFailed  = 5 // FFmpeg ran but the conversion failed
+)
+
+// usageError is an invalid flag, argument or combination of options
+type usageError struct{ err error }
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// inputNotFoundError is an input file that doesn't exist
+type inputNotFoundError struct {
+	Kind string // e.g. "input file" or "subtitle file"
+	Path string
+}
+
+func (e *inputNotFoundError) Error() string {
+	return fmt.Sprintf("%s does not exist: %s", e.Kind, e.Path)
+}
+
+// ffmpegUnavailableError means FFmpeg couldn't be found or run
+type ffmpegUnavailableError struct{ err error }
+
+func (e *ffmpegUnavailableError) Error() string { return e.err.Error() }
+func (e *ffmpegUnavailableError) Unwrap() error { return e.err }
+
+// conversionError means FFmpeg ran but failed to produce the output
+type conversionError struct{ err error }
+
+func (e *conversionError) Error() string { return e.err.Error() }
+func (e *conversionError) Unwrap() error { return e.err }
+
+// usageErrorf formats an error about invalid options
+func usageErrorf(format string, args ...any) error {
+	return &usageError{fmt.Errorf(format, args...)}
+}
+
+// asUsageError marks an error from validating options as a usage error
+func asUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &usageError{err}
+}
+
+// usageArgs marks errors from a command's argument validation as usage errors
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		return asUsageError(validate(cmd, args))
+	}
+}
+
+// exitCode returns the process exit code for an error returned by a command
+func exitCode(err error) int {
+	var (
+		notFound    *inputNotFoundError
+		unavailable *ffmpegUnavailableError
+		conversion  *conversionError
+		ffmpegErr   *gifmaker.FFmpegError
+		usage       *usageError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &notFound):
+		return exitInputNotFound
+	case errors.As(err, &unavailable):
+		return exitFFmpegUnavailable
+	case errors.As(err, &conversion), errors.As(err, &ffmpegErr):
+		return exitConversionFailed
+	case errors.As(err, &usage):
+		return exitUsage
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,143 @@
+// cmd/extractframe.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+type ExtractFrameOptions struct {
+	Time   string
+	Output string
+	Format string
+	Width  int
+}
+
+var frameOpts ExtractFrameOptions
+
+// List of supported still image formats
+var validFrameFormats = []string{"png", "jpg"}
+
+var extractFrameCmd = &cobra.Command{
+	Use:   "extract-frame [video file]",
+	Short: "Extract a single frame from a video as a PNG or JPEG",
+	Long: `Extract a single still frame from a video file, for example to use as a thumbnail.
+The frame is taken at --time and can optionally be scaled with --width.`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		videoPath := args[0]
+
+		// Validate input file exists
+		if _, err := os.Stat(videoPath); os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "input file", Path: videoPath}
+		}
+
+		// Make sure the input is a decodable video
+		if err := checkVideoInput(videoPath); err != nil {
+			return err
+		}
+
+		frameOpts.Format = strings.ToLower(frameOpts.Format)
+		if frameOpts.Format == "jpeg" {
+			frameOpts.Format = "jpg"
+		}
+		if !isValidFrameFormat(frameOpts.Format) {
+			return usageErrorf("invalid format %q (valid: %s)", frameOpts.Format, strings.Join(validFrameFormats, ", "))
+		}
+
+		if !ValidateTimeFormat(frameOpts.Time) {
+			return usageErrorf("invalid time format: %s (expected HH:MM:SS, MM:SS or seconds)", frameOpts.Time)
+		}
+
+		if frameOpts.Width < 0 {
+			return usageErrorf("invalid width value: %d", frameOpts.Width)
+		}
+
+		// Set default output if not provided
+		if frameOpts.Output == "" {
+			inputBase := filepath.Base(videoPath)
+			frameOpts.Output = strings.TrimSuffix(inputBase, filepath.Ext(inputBase)) + "." + frameOpts.Format
+		}
+
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		if err := extractFrame(ffmpegPath, videoPath, frameOpts.Time, frameOpts.Output, frameOpts.Width); err != nil {
+			return err
+		}
+
+		fileInfo, err := os.Stat(frameOpts.Output)
+		if err != nil {
+			return fmt.Errorf("failed to get output file info: %w", err)
+		}
+
+		color.Green("✅ Frame at %s saved to %s (%s)", frameOpts.Time, frameOpts.Output, HumanizeBytes(fileInfo.Size()))
+		return nil
+	},
+}
+
+func init() {
+	extractFrameCmd.Flags().StringVarP(&frameOpts.Time, "time", "t", "00:00:00", "Time of the frame to extract (format: 00:00:00)")
+	extractFrameCmd.Flags().StringVarP(&frameOpts.Output, "output", "o", "", "Output image file (default: input_name.png)")
+	extractFrameCmd.Flags().StringVar(&frameOpts.Format, "format", "png", "Output image format (png, jpg)")
+	extractFrameCmd.Flags().IntVarP(&frameOpts.Width, "width", "w", 0, "Output width in pixels (default: same as input)")
+
+	rootCmd.AddCommand(extractFrameCmd)
+}
+
+// isValidFrameFormat checks if the still image format is supported
+func isValidFrameFormat(format string) bool {
+	return slices.Contains(validFrameFormats, format)
+}
+
+// extractFrame writes the frame at timeStr to output, scaled to width if set.
+// The image format is chosen by FFmpeg from the output extension.
+func extractFrame(ffmpegPath, input, timeStr, output string, width int) error {
+	logger := GetLogger()
+
+	ffmpegArgs := []string{
+		"-y",
+		"-loglevel", "error",
+	}
+
+	if timeStr != "" {
+		ffmpegArgs = append(ffmpegArgs, "-ss", timeStr)
+	}
+
+	ffmpegArgs = append(ffmpegArgs, "-i", input, "-frames:v", "1")
+
+	if width > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-vf", gifmaker.ScaleFilter(width))
+	}
+
+	// Use high JPEG quality; PNG is lossless anyway
+	ext := strings.ToLower(filepath.Ext(output))
+	if ext == ".jpg" || ext == ".jpeg" {
+		ffmpegArgs = append(ffmpegArgs, "-q:v", "2")
+	}
+
+	ffmpegArgs = append(ffmpegArgs, output)
+
+	logger.Debugf("FFmpeg command: %s %s", ffmpegPath, strings.Join(ffmpegArgs, " "))
+	if out, err := exec.Command(ffmpegPath, ffmpegArgs...).CombinedOutput(); err != nil {
+		return &conversionError{fmt.Errorf("failed to extract frame: %w\nError output: %s", err, strings.TrimSpace(string(out)))}
+	}
+
+	// FFmpeg succeeds without writing anything if the time is past the end
+	if _, err := os.Stat(output); err != nil {
+		return fmt.Errorf("no frame found at %s in %s", timeStr, input)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// cmd/gifinput.go
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/Akashdeep-Patra/gif-maker/internal/gifopt"
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Convert flags the GIF input path handles itself. Any other flag needs
+// the full FFmpeg pipeline.
+var gifInputFlags = []string{
+	"input", "output", "input-list", "interactive", "format", "width", "height",
+	"quality", "start", "duration", "end", "no-overwrite", "no-progress",
+	"dry-run", "upload", "clipboard", "force-reencode", "poster",
+	"strip-metadata", "comment",
+}
+
+// isGIFInput reports whether the input is a GIF, by its extension or, for
+// files without one, its content. ffprobe has the final say.
+func isGIFInput(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".gif") {
+		if container, _ := sniffContainer(path); container != "gif" {
+			return false
+		}
+	}
+	video, err := ProbeVideo(path)
+	if err != nil || !video.HasVideo() {
+		return true
+	}
+	return video.Codec == "gif"
+}
+
+// fullPipelineFlag returns the first flag set on the command that the GIF
+// input path can't handle, or "" if it can handle all of them
+func fullPipelineFlag(cmd *cobra.Command) string {
+	var unsupported string
+	cmd.LocalFlags().Visit(func(f *pflag.Flag) {
+		if unsupported != "" {
+			return
+		}
+		for _, name := range gifInputFlags {
+			if f.Name == name {
+				return
+			}
+		}
+		unsupported = f.Name
+	})
+	if unsupported == "" && opts.Format != gifmaker.FormatGIF {
+		unsupported = "format"
+	}
+	return unsupported
+}
+
+// convertGIFInput trims, resizes and reduces the colors of a GIF input in
+// pure Go. Frames keep their palettes and pixels are remapped by index, so
+// the GIF isn't quantized and dithered a second time.
+func convertGIFInput() error {
+	logger := GetLogger()
+	logger.Infof("Optimizing GIF input: %s -> %s", opts.Input, opts.Output)
+
+	start, err := gifmaker.TimeToSeconds(opts.Start)
+	if err != nil {
+		return err
+	}
+	duration, err := gifmaker.TimeToSeconds(opts.Duration)
+	if err != nil {
+		return err
+	}
+	if opts.End != "" {
+		if duration, err = clipDurationFromEnd(opts.Start, opts.End); err != nil {
+			return err
+		}
+	}
+	colors, _ := gifmaker.QualityPalette(opts.Quality)
+	transform := gifopt.Transform{
+		Start:    start,
+		Duration: duration,
+		Width:    opts.Width,
+		Height:   opts.Height,
+		Colors:   colors,
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Dry run: would trim and resize %s to %s, keeping at most %d colors per palette\n", opts.Input, opts.Output, colors)
+		return nil
+	}
+
+	if err := ensureOutputDir(opts.Output); err != nil {
+		return err
+	}
+	unlock, err := acquireOutputLock(opts.Output)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	startTime := time.Now()
+	stats, err := gifopt.TransformFile(opts.Input, opts.Output, transform)
+	if err != nil {
+		return &conversionError{fmt.Errorf("failed to optimize %s: %w", opts.Input, err)}
+	}
+	if err := addOutputComments([]string{opts.Output}); err != nil {
+		return err
+	}
+	elapsed := time.Since(startTime).Seconds()
+
+	width, height := 0, 0
+	if analysis, err := analyzeGIF(opts.Output); err == nil {
+		width, height = analysis.Width, analysis.Height
+	}
+
+	fmt.Println()
+	color.New(color.FgHiGreen, color.Bold).Println("✅ GIF optimized successfully!")
+	fmt.Println()
+	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), opts.Output)
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Size:"), fmt.Sprintf("%s → %s", HumanizeBytes(stats.BytesIn), HumanizeBytes(stats.BytesOut)))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Dimensions:"), fmt.Sprintf("%dx%d", width, height))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Frames:"), fmt.Sprintf("%d of %d", stats.FramesOut, stats.FramesIn))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Time:"), fmt.Sprintf("%.1f seconds", elapsed))
+	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+
+	logger.Infof("GIF optimized: %s (%d -> %d bytes, %d -> %d frames) in %.1f seconds",
+		opts.Output, stats.BytesIn, stats.BytesOut, stats.FramesIn, stats.FramesOut, elapsed)
+	return nil
+}
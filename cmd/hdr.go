@@ -0,0 +1,41 @@
+// cmd/hdr.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// isHDR reports whether the probed video stream uses an HDR transfer, as
+// recorded by most recent phones
+func isHDR(info *VideoInfo) bool {
+	return gifmaker.IsHDRTransfer(info.ColorTransfer)
+}
+
+// warnColorSettings warns about color settings that likely don't suit
+// the probed source
+func warnColorSettings(info *VideoInfo) {
+	logger := GetLogger()
+
+	var warning string
+	switch {
+	case isHDR(info) && opts.Tonemap == "":
+		warning = fmt.Sprintf("%s is HDR (%s transfer); without --tonemap the GIF will look washed out (try --tonemap %s)",
+			opts.Input, info.ColorTransfer, gifmaker.TonemapHable)
+	case !isHDR(info) && opts.Tonemap != "":
+		logger.Infof("%s doesn't look like HDR (transfer %q); tone mapping it anyway", opts.Input, info.ColorTransfer)
+	}
+	if warning != "" {
+		color.Yellow("⚠️ %s", warning)
+		logger.Warn(warning)
+	}
+
+	if opts.ColorRange != "" {
+		if tagged := info.ColorRange; tagged != "" && tagged != opts.ColorRange {
+			logger.Infof("%s is tagged as %s range; reading it as %s as requested", opts.Input, tagged, opts.ColorRange)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// cmd/history.go
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Maximum number of entries kept in each history list
+const maxHistoryEntries = 10
+
+// History remembers recent interactive-mode choices between runs
+type History struct {
+	Inputs     []string `json:"inputs"`
+	OutputDirs []string `json:"output_dirs"`
+}
+
+// historyPath returns the location of the history state file
+func historyPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find config directory: %w", err)
+	}
+	return filepath.Join(configDir, "gif-maker", "history.json"), nil
+}
+
+// loadHistory reads the history file, returning an empty history if it
+// doesn't exist or can't be read
+func loadHistory() *History {
+	h := &History{}
+
+	path, err := historyPath()
+	if err != nil {
+		return h
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		GetLogger().Warnf("Ignoring unreadable history file %s: %v", path, err)
+		return &History{}
+	}
+
+	return h
+}
+
+// Save writes the history file
+func (h *History) Save() error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+// AddInput records an input file as the most recent one
+func (h *History) AddInput(input string) {
+	if abs, err := filepath.Abs(input); err == nil {
+		input = abs
+	}
+	h.Inputs = pushRecent(h.Inputs, input)
+}
+
+// AddOutput records the directory of an output file as the most recent one
+func (h *History) AddOutput(output string) {
+	if abs, err := filepath.Abs(output); err == nil {
+		output = abs
+	}
+	h.OutputDirs = pushRecent(h.OutputDirs, filepath.Dir(output))
+}
+
+// LastInput returns the most recently used input file, or ""
+func (h *History) LastInput() string {
+	if len(h.Inputs) == 0 {
+		return ""
+	}
+	return h.Inputs[0]
+}
+
+// LastInputDir returns the directory of the most recently used input, or ""
+func (h *History) LastInputDir() string {
+	if last := h.LastInput(); last != "" {
+		return filepath.Dir(last)
+	}
+	return ""
+}
+
+// LastOutputDir returns the most recently used output directory, or ""
+func (h *History) LastOutputDir() string {
+	if len(h.OutputDirs) == 0 {
+		return ""
+	}
+	return h.OutputDirs[0]
+}
+
+// clearHistory removes the history file
+func clearHistory() error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear history: %w", err)
+	}
+
+	return nil
+}
+
+// pushRecent moves value to the front of list, removing duplicates and
+// capping the list at maxHistoryEntries
+func pushRecent(list []string, value string) []string {
+	result := []string{value}
+	for _, existing := range list {
+		if existing != value && len(result) < maxHistoryEntries {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
@@ -4,27 +4,35 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
 )
 
+// Whether info should also run crop detection
+var infoSuggestCrop bool
+
 var infoCmd = &cobra.Command{
 	Use:   "info [video file]",
 	Short: "Display information about a video file",
-	Args:  cobra.ExactArgs(1),
+	Args:  usageArgs(cobra.ExactArgs(1)),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		videoPath := args[0]
+		videoPath := expandPath(args[0])
 
 		// Check if the file exists
 		if _, err := os.Stat(videoPath); os.IsNotExist(err) {
-			return fmt.Errorf("video file does not exist: %s", videoPath)
+			return &inputNotFoundError{Kind: "video file", Path: videoPath}
+		}
+
+		// Make sure it's a video before reporting on it
+		if err := checkVideoInput(videoPath); err != nil {
+			return err
 		}
 
 		// Get video information
-		info, err := GetVideoInfo(videoPath)
+		video, err := ProbeVideo(videoPath)
 		if err != nil {
 			return fmt.Errorf("failed to get video information: %w", err)
 		}
@@ -41,68 +49,91 @@ var infoCmd = &cobra.Command{
 
 		fmt.Printf("Size:      %s\n", HumanizeBytes(stat.Size()))
 
-		if width, ok := info["width"]; ok {
-			fmt.Printf("Width:     %s px\n", width)
+		if video.Width > 0 {
+			fmt.Printf("Width:     %d px\n", video.Width)
 		}
 
-		if height, ok := info["height"]; ok {
-			fmt.Printf("Height:    %s px\n", height)
+		if video.Height > 0 {
+			fmt.Printf("Height:    %d px\n", video.Height)
 		}
 
-		if duration, ok := info["duration"]; ok {
-			durationFloat, err := strconv.ParseFloat(duration, 64)
-			if err == nil {
-				minutes := int(durationFloat) / 60
-				seconds := int(durationFloat) % 60
-				fmt.Printf("Duration:  %d:%02d (%.2f seconds)\n", minutes, seconds, durationFloat)
-			} else {
-				fmt.Printf("Duration:  %s seconds\n", duration)
-			}
+		if video.Duration > 0 {
+			minutes := int(video.Duration) / 60
+			seconds := int(video.Duration) % 60
+			fmt.Printf("Duration:  %d:%02d (%.2f seconds)\n", minutes, seconds, video.Duration)
+		}
+
+		if video.FrameRate > 0 {
+			fmt.Printf("FPS:       %.2f\n", video.FrameRate)
 		}
 
-		if frameRate, ok := info["r_frame_rate"]; ok {
-			// Frame rate can be in the format "30000/1001" (for 29.97 fps)
-			if strings.Contains(frameRate, "/") {
-				parts := strings.Split(frameRate, "/")
-				if len(parts) == 2 {
-					num, err1 := strconv.ParseFloat(parts[0], 64)
-					den, err2 := strconv.ParseFloat(parts[1], 64)
-					if err1 == nil && err2 == nil && den > 0 {
-						fps := num / den
-						fmt.Printf("FPS:       %.2f\n", fps)
-					} else {
-						fmt.Printf("FPS:       %s\n", frameRate)
-					}
-				}
+		if video.ColorTransfer != "" {
+			colorRange := video.ColorRange
+			if colorRange == "" {
+				colorRange = "unknown"
+			}
+			if isHDR(video) {
+				fmt.Printf("Color:     %s, %s range (HDR, convert with --tonemap)\n", video.ColorTransfer, colorRange)
 			} else {
-				fmt.Printf("FPS:       %s\n", frameRate)
+				fmt.Printf("Color:     %s, %s range\n", video.ColorTransfer, colorRange)
 			}
 		}
 
 		// Calculate estimated GIF sizes
-		if width, ok := info["width"]; ok {
-			if height, ok2 := info["height"]; ok2 {
-				if duration, ok3 := info["duration"]; ok3 {
-					w, _ := strconv.Atoi(width)
-					h, _ := strconv.Atoi(height)
-					d, _ := strconv.ParseFloat(duration, 64)
-
-					// Rough estimation for different FPS values
-					fmt.Println("\nEstimated GIF sizes (rough approximation):")
-					for _, fps := range []int{5, 10, 15, 20} {
-						// Very rough approximation: pixels * frames * bytes per pixel / compression factor
-						frames := int(d) * fps
-						sizeBytes := float64(w*h*frames*3) / 4.0 // Assuming some compression
-						fmt.Printf("  At %d FPS: ~%s\n", fps, HumanizeBytes(int64(sizeBytes)))
-					}
-				}
+		if video.Width > 0 && video.Height > 0 && video.Duration > 0 {
+			// Rough estimation for different FPS values
+			fmt.Println("\nEstimated GIF sizes (256 colors, dithered):")
+			for _, fps := range []int{5, 10, 15, 20} {
+				frames := int(video.Duration) * fps
+				fmt.Printf("  At %d FPS: ~%s\n", fps, HumanizeBytes(EstimateGIFSize(video.Width, video.Height, frames, defaultPaletteColors, true)))
 			}
 		}
 
+		if infoSuggestCrop {
+			return printCropSuggestion(videoPath, video)
+		}
+
 		return nil
 	},
 }
 
 func init() {
+	infoCmd.Flags().BoolVar(&infoSuggestCrop, "suggest-crop", false, "Run crop detection and suggest a value for convert --crop")
+
 	rootCmd.AddCommand(infoCmd)
 }
+
+// printCropSuggestion runs cropdetect on the video and prints the crop it
+// suggests, in the orientation convert will use
+func printCropSuggestion(videoPath string, video *VideoInfo) error {
+	ffmpegPath, err := ffmpegManager.GetPath()
+	if err != nil {
+		return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+	}
+
+	width, height := video.Width, video.Height
+	rotation := video.Rotation
+	if rotation == 90 || rotation == 270 {
+		width, height = height, width
+	}
+
+	// Skip into the video a little, since intros are often black
+	start := ""
+	if video.Duration > 2*cropDetectSeconds {
+		start = formatSeconds(video.Duration / 10)
+	}
+
+	fmt.Println("\nDetecting black bars...")
+	crop, err := detectCrop(ffmpegPath, videoPath, start, gifmaker.TransformFilter(rotation, ""), true, width, height)
+	if err != nil {
+		return err
+	}
+	if crop == "" {
+		fmt.Println("No black bars detected, no crop needed")
+		return nil
+	}
+
+	fmt.Printf("Suggested crop: %s\n", color.CyanString(crop))
+	fmt.Printf("  Use it with: gif-maker convert -i %s --crop %s\n", videoPath, crop)
+	return nil
+}
@@ -0,0 +1,38 @@
+// cmd/interpolate.go
+package cmd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// warnInterpolate warns that interpolation is slow, and pointless if the
+// source already has at least as many frames as the GIF will
+func warnInterpolate(info *VideoInfo) {
+	logger := GetLogger()
+
+	if info != nil {
+		opts.sourceFPS = info.FrameRate
+	}
+
+	var warning string
+	switch {
+	case opts.sourceFPS > 0 && math.Round(opts.sourceFPS) >= float64(opts.FPS):
+		warning = fmt.Sprintf("%s already runs at %.2f fps, so interpolating to %d fps adds no frames, only time; drop --interpolate or raise --fps",
+			opts.Input, opts.sourceFPS, opts.FPS)
+	case opts.Interpolate == gifmaker.InterpolateMCI:
+		warning = "--interpolate mci estimates motion for every frame and can take many times longer than a normal conversion (blend is much faster)"
+	default:
+		warning = "--interpolate blends frames for every output frame, so the conversion will be noticeably slower"
+	}
+	color.Yellow("⚠️ %s", warning)
+	logger.Warn(warning)
+
+	if opts.sourceFPS > 0 {
+		logger.Infof("Interpolating from %.2f fps to %d fps (%s)", opts.sourceFPS, opts.FPS, opts.Interpolate)
+	}
+}
@@ -0,0 +1,37 @@
+// cmd/limits.go
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// startFFmpeg starts an FFmpeg command, at a lower priority if --nice is set
+func startFFmpeg(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if opts.Nice > 0 {
+		if err := gifmaker.SetNice(cmd.Process, opts.Nice); err != nil {
+			GetLogger().Warnf("Could not lower FFmpeg's priority: %v", err)
+		}
+	}
+	return nil
+}
+
+// runFFmpeg runs FFmpeg to completion and returns its combined output.
+// FFmpeg is killed if ctx ends first, e.g. when --timeout runs out.
+func runFFmpeg(ctx context.Context, ffmpegPath string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := startFFmpeg(cmd); err != nil {
+		return nil, err
+	}
+	err := cmd.Wait()
+	return output.Bytes(), err
+}
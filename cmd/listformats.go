@@ -0,0 +1,234 @@
+// cmd/listformats.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// List every demuxer instead of the common video containers
+var listFormatsAll bool
+
+// inputContainer is a video container worth listing by name, identified by
+// the FFmpeg demuxer that reads it
+type inputContainer struct {
+	Demuxer    string
+	Name       string
+	Extensions string
+}
+
+// Video containers people actually convert from, in the order listed
+var inputContainers = []inputContainer{
+	{"mov", "MP4 / QuickTime", ".mp4 .mov .m4v .3gp"},
+	{"matroska", "Matroska / WebM", ".mkv .webm"},
+	{"avi", "AVI", ".avi"},
+	{"flv", "Flash Video", ".flv"},
+	{"mpegts", "MPEG transport stream", ".ts .mts .m2ts"},
+	{"mpeg", "MPEG program stream", ".mpg .mpeg .vob"},
+	{"asf", "Windows Media", ".wmv .asf"},
+	{"ogg", "Ogg", ".ogv"},
+	{"mxf", "MXF", ".mxf"},
+	{"gif", "GIF", ".gif"},
+	{"apng", "Animated PNG", ".apng"},
+	{"h264", "Raw H.264 (--input-format h264)", ".h264 .264"},
+	{"hevc", "Raw H.265 (--input-format hevc)", ".h265 .hevc"},
+	{"rawvideo", "Raw frames (--input-format rawvideo)", ".yuv .raw"},
+}
+
+// outputTarget is an output format gif-maker writes and what it needs from
+// FFmpeg to write it
+type outputTarget struct {
+	Format  string
+	Muxer   string
+	Encoder string
+}
+
+// The --format values, with the muxer and encoder each one uses
+var outputTargets = []outputTarget{
+	{gifmaker.FormatGIF, "gif", "gif"},
+	{gifmaker.FormatWebM, "webm", "libvpx-vp9"},
+	{gifmaker.FormatMP4, "mp4", "libx264"},
+}
+
+// ffmpegFormat is one line of `ffmpeg -formats`
+type ffmpegFormat struct {
+	Names       []string // A demuxer can go by several names, e.g. mov,mp4,m4a
+	Description string
+	Demux       bool
+	Mux         bool
+}
+
+var listFormatsCmd = &cobra.Command{
+	Use:   "list-formats",
+	Short: "Show which input containers and output formats the FFmpeg in use supports",
+	Long: `Ask the FFmpeg that conversions use (the embedded one, or the system
+FFmpeg if none is embedded) which containers it can read and whether it
+has the muxers and encoders needed for each --format.
+
+By default only common video containers are listed; --all lists every
+format FFmpeg can read.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkFFmpegInstallation(); err != nil {
+			return err
+		}
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		formatsOutput, err := exec.Command(ffmpegPath, "-hide_banner", "-formats").Output()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("failed to list FFmpeg formats: %w", err)}
+		}
+		encodersOutput, err := exec.Command(ffmpegPath, "-hide_banner", "-encoders").Output()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("failed to list FFmpeg encoders: %w", err)}
+		}
+		formats := parseFFmpegFormats(string(formatsOutput))
+		encoders := parseFFmpegEncoders(string(encodersOutput))
+
+		color.Green("Input containers")
+		if listFormatsAll {
+			printAllDemuxers(formats)
+		} else {
+			printInputContainers(formats)
+		}
+
+		fmt.Println()
+		color.Green("Output formats (--format)")
+		fmt.Printf("  %-8s %-8s %-12s %s\n", "FORMAT", "MUXER", "ENCODER", "STATUS")
+		for _, target := range outputTargets {
+			status := color.GreenString("supported")
+			switch {
+			case !hasMuxer(formats, target.Muxer):
+				status = color.RedString("missing the %s muxer", target.Muxer)
+			case !encoders[target.Encoder]:
+				status = color.RedString("missing the %s encoder", target.Encoder)
+			}
+			fmt.Printf("  %-8s %-8s %-12s %s\n", target.Format, target.Muxer, target.Encoder, status)
+		}
+		return nil
+	},
+}
+
+func init() {
+	listFormatsCmd.Flags().BoolVar(&listFormatsAll, "all", false, "List every format FFmpeg can read, not just common video containers")
+
+	rootCmd.AddCommand(listFormatsCmd)
+}
+
+// printInputContainers prints the common video containers FFmpeg can read
+func printInputContainers(formats []ffmpegFormat) {
+	fmt.Printf("  %-38s %s\n", "CONTAINER", "EXTENSIONS")
+	missing := 0
+	for _, c := range inputContainers {
+		if !hasDemuxer(formats, c.Demuxer) {
+			missing++
+			continue
+		}
+		fmt.Printf("  %-38s %s\n", c.Name, c.Extensions)
+	}
+	if missing > 0 {
+		color.Yellow("  (%d common containers are missing from this FFmpeg build)", missing)
+	}
+	fmt.Println("  Run with --all to see every format FFmpeg can read")
+}
+
+// printAllDemuxers prints every format FFmpeg can read, sorted by name
+func printAllDemuxers(formats []ffmpegFormat) {
+	var demuxers []ffmpegFormat
+	for _, f := range formats {
+		if f.Demux {
+			demuxers = append(demuxers, f)
+		}
+	}
+	sort.Slice(demuxers, func(i, j int) bool { return demuxers[i].Names[0] < demuxers[j].Names[0] })
+
+	fmt.Printf("  %-24s %s\n", "NAME", "DESCRIPTION")
+	for _, f := range demuxers {
+		fmt.Printf("  %-24s %s\n", strings.Join(f.Names, ","), f.Description)
+	}
+	fmt.Printf("  %d formats\n", len(demuxers))
+}
+
+// parseFFmpegFormats parses the table printed by `ffmpeg -formats`. Each
+// row after the "--" separator ("---" since FFmpeg 6.1) has flags (D for
+// demuxing, E for muxing and, since 6.1, d for devices), the names and a
+// description. Devices such as webcams aren't files, so they are left out.
+func parseFFmpegFormats(output string) []ffmpegFormat {
+	var formats []ffmpegFormat
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		if separator := strings.TrimSpace(line); separator == "--" || separator == "---" {
+			inTable = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if !inTable || len(fields) < 2 {
+			continue
+		}
+
+		flags := fields[0]
+		if strings.Contains(flags, "d") {
+			continue
+		}
+		formats = append(formats, ffmpegFormat{
+			Names:       strings.Split(fields[1], ","),
+			Description: strings.Join(fields[2:], " "),
+			Demux:       strings.Contains(flags, "D"),
+			Mux:         strings.Contains(flags, "E"),
+		})
+	}
+	return formats
+}
+
+// parseFFmpegEncoders returns the names of the encoders listed by
+// `ffmpeg -encoders`, whose rows follow a "------" separator
+func parseFFmpegEncoders(output string) map[string]bool {
+	encoders := make(map[string]bool)
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "------" {
+			inTable = true
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			encoders[fields[1]] = true
+		}
+	}
+	return encoders
+}
+
+// hasDemuxer reports whether FFmpeg can read the named format
+func hasDemuxer(formats []ffmpegFormat, name string) bool {
+	return findFormat(formats, name, func(f ffmpegFormat) bool { return f.Demux })
+}
+
+// hasMuxer reports whether FFmpeg can write the named format
+func hasMuxer(formats []ffmpegFormat, name string) bool {
+	return findFormat(formats, name, func(f ffmpegFormat) bool { return f.Mux })
+}
+
+// findFormat reports whether a format going by name matches ok. A name
+// can be listed twice, e.g. once as a demuxer and once as a muxer.
+func findFormat(formats []ffmpegFormat, name string, ok func(ffmpegFormat) bool) bool {
+	for _, f := range formats {
+		for _, n := range f.Names {
+			if n == name && ok(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// cmd/lock.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// lockPath returns the path of the advisory lockfile for an output file
+func lockPath(output string) string {
+	return output + ".lock"
+}
+
+// acquireOutputLock creates <output>.lock holding our PID so that two runs
+// can't write the same output at once. A lock left behind by a process that
+// no longer exists is reclaimed. The returned function removes the lock.
+func acquireOutputLock(output string) (func(), error) {
+	path := lockPath(output)
+
+	// Two attempts: the second one follows removing a stale lock
+	for attempt := 0; attempt < 2; attempt++ {
+		err := createLockfile(path)
+		if err == nil {
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		// The lock is created with its PID already in it, so one we can't
+		// read is not half-written: leave it to whoever made it
+		pid, err := readLockPID(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s is locked by %s, which can't be read (%v); if no other gif-maker run is writing it, remove the lockfile", output, path, err)
+		}
+		if processAlive(pid) {
+			return nil, fmt.Errorf("%s is already being written by another gif-maker run (PID %d); if that's not the case, remove %s", output, pid, path)
+		}
+
+		// Another run may have reclaimed the lock since we read it, so only
+		// remove it if it still holds the same dead PID
+		if again, err := readLockPID(path); err != nil || again != pid {
+			continue
+		}
+		GetLogger().Warnf("Removing stale lockfile %s (PID %d is no longer running)", path, pid)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lockfile %s: %w", path, err)
+		}
+	}
+
+	return nil, fmt.Errorf("could not acquire lockfile %s", path)
+}
+
+// createLockfile atomically creates path holding our PID. The PID goes into
+// a temp file first, which is then hard-linked into place, so other runs
+// never see the lockfile empty. It fails with os.ErrExist if path exists.
+func createLockfile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create lockfile %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(strconv.Itoa(os.Getpid()))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return err
+		}
+		return fmt.Errorf("failed to create lockfile %s: %w", path, err)
+	}
+	return nil
+}
+
+// readLockPID returns the PID stored in a lockfile
+func readLockPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid PID in lockfile %s", path)
+	}
+	return pid, nil
+}
+
+// processAlive reports whether a process with the given PID is running
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// On Windows FindProcess fails for processes that don't exist. On Unix
+	// it always succeeds, so probe with signal 0 instead.
+	if runtime.GOOS == "windows" {
+		p.Release()
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
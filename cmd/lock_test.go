@@ -0,0 +1,86 @@
+// cmd/lock_test.go
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestAcquireOutputLock(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.gif")
+
+	unlock, err := acquireOutputLock(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid, err := readLockPID(lockPath(output)); err != nil || pid != os.Getpid() {
+		t.Errorf("lockfile holds PID %d (%v), want %d", pid, err, os.Getpid())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(output))
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %d entries in the directory", len(entries))
+	}
+
+	// We're alive, so a second run has to wait its turn
+	if _, err := acquireOutputLock(output); err == nil {
+		t.Error("second acquireOutputLock succeeded while the lock was held")
+	}
+
+	unlock()
+	if _, err := os.Stat(lockPath(output)); !os.IsNotExist(err) {
+		t.Errorf("lockfile still there after unlock (stat error %v)", err)
+	}
+}
+
+func TestAcquireOutputLockExisting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("processAlive can't tell dead processes apart on Windows")
+	}
+
+	// A process that has already exited gives us a dead PID
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	deadPID := strconv.Itoa(cmd.Process.Pid)
+
+	tests := []struct {
+		name     string
+		contents string
+		reclaim  bool
+	}{
+		{"dead PID", deadPID, true},
+		{"live PID", strconv.Itoa(os.Getpid()), false},
+		{"empty", "", false},
+		{"garbage", "not a pid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "out.gif")
+			if err := os.WriteFile(lockPath(output), []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			unlock, err := acquireOutputLock(output)
+			if tt.reclaim {
+				if err != nil {
+					t.Fatalf("stale lock not reclaimed: %v", err)
+				}
+				unlock()
+				return
+			}
+			if err == nil {
+				unlock()
+				t.Fatal("lock was reclaimed, want an error")
+			}
+			data, _ := os.ReadFile(lockPath(output))
+			if string(data) != tt.contents {
+				t.Errorf("lockfile now holds %q, want it untouched", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// cmd/optimize.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Akashdeep-Patra/gif-maker/internal/gifopt"
+)
+
+// optimizeOutputs merges duplicate frames in the finished GIFs with the
+// pure-Go optimizer. Failures only produce a warning since the unoptimized
+// GIF is still usable.
+func optimizeOutputs(progress *ProgressData) {
+	logger := GetLogger()
+
+	for i, output := range conversionOutputs() {
+		fmt.Printf("Optimizing %s...\n", output)
+		stats, err := gifopt.OptimizeFile(output, gifopt.Options{Threshold: gifopt.DefaultThreshold})
+		if errors.Is(err, gifopt.ErrTransparent) {
+			logger.Warnf("Skipping optimization of %s: %v", output, err)
+			continue
+		} else if err != nil {
+			logger.Warnf("Could not optimize %s: %v", output, err)
+			continue
+		}
+
+		if stats.FramesOut == stats.FramesIn {
+			fmt.Println("  No duplicate frames to merge")
+			continue
+		}
+		fmt.Printf("  Merged %d duplicate frames: %s -> %s\n",
+			stats.FramesIn-stats.FramesOut, HumanizeBytes(stats.BytesIn), HumanizeBytes(stats.BytesOut))
+
+		// The summary reports the frames of the (first) GIF
+		if i == 0 {
+			progress.Frames = stats.FramesOut
+		}
+	}
+}
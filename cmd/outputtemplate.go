@@ -0,0 +1,113 @@
+// cmd/outputtemplate.go
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Placeholders an output template may use
+var outputPlaceholders = []string{"name", "ext", "width", "height", "fps", "index", "date"}
+
+// isValidOutputPlaceholder checks if a placeholder is supported
+func isValidOutputPlaceholder(name string) bool {
+	return slices.Contains(outputPlaceholders, name)
+}
+
+// validateOutputTemplate checks that every {placeholder} in the template is
+// known and every brace is closed
+func validateOutputTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return fmt.Errorf("output template is empty")
+	}
+	rest := tmpl
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			return nil
+		}
+		if rest[open] == '}' {
+			return fmt.Errorf("unmatched } in output template %q", tmpl)
+		}
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] != '}' {
+			return fmt.Errorf("unclosed { in output template %q", tmpl)
+		}
+		name := rest[open+1 : open+1+end]
+		if !isValidOutputPlaceholder(name) {
+			return fmt.Errorf("unknown placeholder {%s} in output template (valid: {%s})", name, strings.Join(outputPlaceholders, "}, {"))
+		}
+		rest = rest[open+end+2:]
+	}
+}
+
+// expandOutputTemplate replaces each {placeholder} with its value. The
+// template must have been validated.
+func expandOutputTemplate(tmpl string, values map[string]string) string {
+	var b strings.Builder
+	rest := tmpl
+	for {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		end := strings.Index(rest[open:], "}")
+		b.WriteString(rest[:open])
+		b.WriteString(values[rest[open+1:open+end]])
+		rest = rest[open+end+1:]
+	}
+}
+
+// outputTemplateValues works out the placeholder values for one input.
+// Sizes and the frame rate come from the options, falling back to the
+// source's when they are left to match it. index is 1-based and padded to
+// the width of total.
+func outputTemplateValues(input string, index, total int, date time.Time) map[string]string {
+	base := filepath.Base(input)
+	ext := filepath.Ext(base)
+	values := map[string]string{
+		"name":  strings.TrimSuffix(base, ext),
+		"ext":   strings.TrimPrefix(ext, "."),
+		"index": fmt.Sprintf("%0*d", len(strconv.Itoa(total)), index),
+		"date":  date.Format("2006-01-02"),
+	}
+
+	var srcWidth, srcHeight int
+	var srcRate float64
+	if video, err := ProbeVideo(input); err == nil {
+		srcWidth, srcHeight = video.Width, video.Height
+		if rotation := video.Rotation; !opts.NoAutorotate && (rotation == 90 || rotation == 270) {
+			srcWidth, srcHeight = srcHeight, srcWidth
+		}
+		srcRate = video.FrameRate
+	} else {
+		GetLogger().Warnf("Could not probe %s for the output template: %v", input, err)
+	}
+
+	width, height := opts.Width, opts.Height
+	switch {
+	case width > 0 && height > 0:
+	case width > 0 && srcWidth > 0:
+		height = int(math.Round(float64(srcHeight*width) / float64(srcWidth)))
+	case height > 0 && srcHeight > 0:
+		width = int(math.Round(float64(srcWidth*height) / float64(srcHeight)))
+	case width == 0 && height == 0:
+		width, height = srcWidth, srcHeight
+	}
+	values["width"] = strconv.Itoa(width)
+	values["height"] = strconv.Itoa(height)
+
+	fps, err := strconv.Atoi(strings.TrimSpace(opts.fpsArg))
+	if err != nil || fps < 1 {
+		fps = autoFPS(srcRate)
+	}
+	values["fps"] = strconv.Itoa(clampFPS(fps, opts.MinFPS, opts.MaxFPS))
+
+	return values
+}
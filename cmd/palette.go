@@ -0,0 +1,155 @@
+// cmd/palette.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+type PaletteOptions struct {
+	Output    string
+	Colors    int
+	StatsMode string
+	FPS       int
+	Start     string
+	Duration  string
+}
+
+var paletteOpts PaletteOptions
+
+// Stats modes that produce a single palette image
+var validPaletteStatsModes = []string{gifmaker.StatsModeDiff, gifmaker.StatsModeFull}
+
+var paletteCmd = &cobra.Command{
+	Use:   "palette [video file]",
+	Short: "Generate a video's GIF palette as a PNG",
+	Long: `Run only the palette generation step on a video and save the palette as a
+16x16 PNG swatch, one pixel per color. The PNG can be inspected in an image
+editor and passed back to convert with --palette-file.`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		videoPath := expandPath(args[0])
+
+		if _, err := os.Stat(videoPath); os.IsNotExist(err) {
+			return &inputNotFoundError{Kind: "input file", Path: videoPath}
+		}
+		if err := checkVideoInput(videoPath); err != nil {
+			return err
+		}
+
+		if paletteOpts.Colors < 2 || paletteOpts.Colors > 256 {
+			return usageErrorf("colors must be between 2 and 256 (got %d)", paletteOpts.Colors)
+		}
+		if !isValidPaletteStatsMode(paletteOpts.StatsMode) {
+			return usageErrorf("invalid stats mode %q (valid: %s)", paletteOpts.StatsMode, strings.Join(validPaletteStatsModes, ", "))
+		}
+		if paletteOpts.FPS < 1 {
+			return usageErrorf("invalid FPS value: %d", paletteOpts.FPS)
+		}
+		for _, t := range []struct{ name, value string }{
+			{"start", paletteOpts.Start},
+			{"duration", paletteOpts.Duration},
+		} {
+			if _, err := gifmaker.TimeToSeconds(t.value); err != nil {
+				return usageErrorf("--%s: %w (expected HH:MM:SS, MM:SS or seconds)", t.name, err)
+			}
+		}
+
+		if paletteOpts.Output == "" {
+			inputBase := filepath.Base(videoPath)
+			paletteOpts.Output = strings.TrimSuffix(inputBase, filepath.Ext(inputBase)) + "-palette.png"
+		}
+		paletteOpts.Output = expandPath(paletteOpts.Output)
+
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		convOpts := gifmaker.Options{
+			FFmpegPath: ffmpegPath,
+			Input:      videoPath,
+			Output:     paletteOpts.Output,
+			FPS:        paletteOpts.FPS,
+			Start:      paletteOpts.Start,
+			Duration:   paletteOpts.Duration,
+			StatsMode:  paletteOpts.StatsMode,
+			MaxColors:  paletteOpts.Colors,
+		}
+		if err := generatePalette(convOpts); err != nil {
+			return err
+		}
+
+		colors, err := countPaletteColors(paletteOpts.Output)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		color.New(color.FgHiGreen, color.Bold).Println("✅ Palette created successfully!")
+		fmt.Println()
+		fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Output:"), paletteOpts.Output)
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Colors:"), fmt.Sprintf("%d distinct (max %d)", colors, paletteOpts.Colors))
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Stats mode:"), paletteOpts.StatsMode)
+		fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+		fmt.Printf("\nUse it with: gif-maker convert --palette-file %s ...\n", paletteOpts.Output)
+
+		return nil
+	},
+}
+
+// generatePalette runs palettegen over the clip and writes the palette PNG
+func generatePalette(convOpts gifmaker.Options) error {
+	logger := GetLogger()
+
+	args := []string{
+		"-y",
+		"-loglevel", "error",
+	}
+	args = append(args, convOpts.InputArgs()...)
+	args = append(args, "-i", convOpts.Input)
+	if convOpts.Start != "" {
+		args = append(args, "-ss", convOpts.Start)
+	}
+	if convOpts.Duration != "" {
+		args = append(args, "-t", convOpts.Duration)
+	}
+	args = append(args,
+		"-vf", fmt.Sprintf("%s,%s", convOpts.BaseFilter(), convOpts.PaletteGenFilter()),
+		"-frames:v", "1",
+		convOpts.Output,
+	)
+
+	fmt.Println("Generating palette...")
+	logger.Debugf("FFmpeg palette command: %s %s", convOpts.FFmpegPath, strings.Join(args, " "))
+	if output, err := exec.Command(convOpts.FFmpegPath, args...).CombinedOutput(); err != nil {
+		return &conversionError{fmt.Errorf("failed to generate palette: %w\nError output: %s", err, strings.TrimSpace(string(output)))}
+	}
+	return nil
+}
+
+// isValidPaletteStatsMode checks if the stats mode produces a single palette
+func isValidPaletteStatsMode(mode string) bool {
+	return slices.Contains(validPaletteStatsModes, mode)
+}
+
+func init() {
+	paletteCmd.Flags().StringVarP(&paletteOpts.Output, "output", "o", "", "Output PNG path (default: input_name-palette.png)")
+	paletteCmd.Flags().IntVar(&paletteOpts.Colors, "colors", 256, "Maximum number of colors in the palette (2-256)")
+	paletteCmd.Flags().StringVar(&paletteOpts.StatsMode, "stats-mode", gifmaker.StatsModeDiff, "How colors are weighted: diff favors what moves, full counts every pixel")
+	paletteCmd.Flags().IntVarP(&paletteOpts.FPS, "fps", "f", 10, "Frames per second sampled from the video")
+	paletteCmd.Flags().StringVar(&paletteOpts.Start, "start", "", "Start time (format: 00:00:00)")
+	paletteCmd.Flags().StringVar(&paletteOpts.Duration, "duration", "", "Duration (format: 00:00:00)")
+
+	rootCmd.AddCommand(paletteCmd)
+}
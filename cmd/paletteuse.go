@@ -0,0 +1,32 @@
+// cmd/paletteuse.go
+package cmd
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Supported paletteuse diff modes
+var validDiffModes = []string{gifmaker.DiffModeRectangle, gifmaker.DiffModeNone}
+
+// isValidDiffMode checks if the diff mode is supported
+func isValidDiffMode(mode string) bool {
+	return slices.Contains(validDiffModes, mode)
+}
+
+// paletteUseFlags are the flags that fine-tune paletteuse
+var paletteUseFlags = []string{"palette-dither-scale", "palette-diff-mode", "palette-new", "palette-alpha-threshold"}
+
+// changedPaletteUseFlag returns the first paletteuse flag given on the
+// command line, or "" if none was
+func changedPaletteUseFlag(cmd *cobra.Command) string {
+	for _, name := range paletteUseFlags {
+		if cmd.Flags().Changed(name) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,383 @@
+// cmd/parallel.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vbauerster/mpb/v7"
+	"github.com/vbauerster/mpb/v7/decor"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// chunk is one time segment of a parallel conversion
+type chunk struct {
+	Index    int
+	Start    float64
+	Duration float64
+	Output   string
+}
+
+// splitIntoChunks divides a clip into n equal time segments
+func splitIntoChunks(start, duration float64, n int) []chunk {
+	if n < 1 || duration <= 0 {
+		return nil
+	}
+
+	chunks := make([]chunk, n)
+	length := duration / float64(n)
+	for i := range chunks {
+		chunks[i] = chunk{
+			Index:    i,
+			Start:    start + float64(i)*length,
+			Duration: length,
+		}
+	}
+
+	// Give any floating point remainder to the last chunk
+	chunks[n-1].Duration = start + duration - chunks[n-1].Start
+
+	return chunks
+}
+
+// parallelProgress aggregates progress reported by concurrent FFmpeg workers
+type parallelProgress struct {
+	mu     sync.Mutex
+	times  []float64
+	frames []int64
+	bar    *mpb.Bar
+}
+
+// update records a worker's progress and refreshes the aggregate bar
+func (p *parallelProgress) update(index int, currentTime float64, frames int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if currentTime > 0 {
+		p.times[index] = currentTime
+	}
+	if frames > 0 {
+		p.frames[index] = frames
+	}
+
+	if p.bar != nil {
+		var total float64
+		for _, t := range p.times {
+			total += t
+		}
+		p.bar.SetCurrent(int64(total * 100))
+	}
+}
+
+// chunkFrames returns the number of frames produced by one worker
+func (p *parallelProgress) chunkFrames(index int) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.frames[index]
+}
+
+// totalFrames returns the number of frames produced by all workers
+func (p *parallelProgress) totalFrames() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var total int64
+	for _, f := range p.frames {
+		total += f
+	}
+	return int(total)
+}
+
+// convertParallel converts the clip as concurrent chunks that share a single
+// palette, then concatenates them into the final GIF
+func convertParallel(ctx context.Context, ffmpegPath string, progress *ProgressData, totalDuration float64) error {
+	logger := GetLogger()
+	convOpts := libraryOptions(ffmpegPath, "none")
+
+	start, duration := resolveClipRange(totalDuration)
+	if duration <= 0 {
+		return fmt.Errorf("could not determine video duration for parallel conversion: %s", opts.Input)
+	}
+
+	// With --resume, intermediate files live in a cache directory named
+	// after the input and options so an interrupted run can pick them up
+	var tempDir string
+	if opts.Resume {
+		key, err := resumeKey(convOpts, opts.Parallel)
+		if err != nil {
+			return fmt.Errorf("failed to compute resume key: %w", err)
+		}
+		if tempDir, err = resumeDir(key); err != nil {
+			return err
+		}
+		logger.Infof("Keeping intermediate files in %s", tempDir)
+	} else {
+		dir, err := os.MkdirTemp(tempRoot, "gif-maker-parallel")
+		if err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		tempDir = dir
+		defer removeTemp(tempDir)
+	}
+
+	// Generate one palette for the whole clip so every chunk uses the same colors,
+	// unless the user brought their own
+	palettePath := opts.PaletteFile
+	if palettePath == "" {
+		palettePath = filepath.Join(tempDir, "palette.png")
+		if _, done := artifactDone(palettePath); opts.Resume && done {
+			fmt.Println("Reusing palette from a previous run")
+		} else if err := generateSharedPalette(ctx, convOpts, palettePath, start, duration); err != nil {
+			return err
+		}
+	}
+
+	chunks := splitIntoChunks(start, duration, opts.Parallel)
+	for i := range chunks {
+		chunks[i].Output = filepath.Join(tempDir, fmt.Sprintf("chunk-%03d.gif", i))
+	}
+
+	tracker := &parallelProgress{
+		times:  make([]float64, len(chunks)),
+		frames: make([]int64, len(chunks)),
+	}
+
+	var p *mpb.Progress
+	if showProgressBars() {
+		p = mpb.New(
+			mpb.WithWidth(progressBarWidth()),
+			mpb.WithRefreshRate(100*time.Millisecond),
+		)
+		tracker.bar = p.AddBar(int64(duration*100),
+			mpb.PrependDecorators(
+				decor.Name(fmt.Sprintf("Converting (%d workers): ", len(chunks))),
+			),
+			mpb.AppendDecorators(
+				decor.Percentage(decor.WC{W: 5}),
+				decor.Name(" • "),
+				decor.Elapsed(decor.ET_STYLE_GO),
+			),
+		)
+	} else {
+		fmt.Printf("Converting %d chunks in parallel...\n", len(chunks))
+	}
+
+	// Convert all chunks concurrently, skipping the ones a previous run finished
+	var wg sync.WaitGroup
+	errs := make([]error, len(chunks))
+	reused := 0
+	for _, c := range chunks {
+		if opts.Resume {
+			if frames, done := artifactDone(c.Output); done {
+				tracker.update(c.Index, c.Duration, frames)
+				reused++
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(c chunk) {
+			defer wg.Done()
+			errs[c.Index] = convertChunk(ctx, convOpts, palettePath, c, tracker)
+			if errs[c.Index] == nil && opts.Resume {
+				errs[c.Index] = markArtifactDone(c.Output, tracker.chunkFrames(c.Index))
+			}
+		}(c)
+	}
+	wg.Wait()
+	if reused > 0 {
+		logger.Infof("Reused %d of %d chunks from a previous run", reused, len(chunks))
+	}
+
+	if tracker.bar != nil {
+		tracker.bar.SetTotal(tracker.bar.Current(), true)
+		p.Wait()
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// Join the chunks, remapping onto the shared palette without re-dithering
+	listPath := filepath.Join(tempDir, "chunks.txt")
+	var list strings.Builder
+	for _, c := range chunks {
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(c.Output, "'", `'\''`))
+	}
+	if err := os.WriteFile(listPath, []byte(list.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write chunk list: %w", err)
+	}
+
+	concatArgs := []string{
+		"-y",
+		"-loglevel", "error",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+		"-i", palettePath,
+		"-filter_complex", "[0:v][1:v]paletteuse=dither=none",
+		opts.Output,
+	}
+	fmt.Println("Joining chunks...")
+	logger.Debugf("FFmpeg concat command: %s %s", ffmpegPath, strings.Join(concatArgs, " "))
+	if output, err := runFFmpeg(ctx, ffmpegPath, concatArgs...); err != nil {
+		if ctx.Err() != nil {
+			os.Remove(opts.Output)
+			return ctx.Err()
+		}
+		return &conversionError{fmt.Errorf("failed to join chunks: %w\nError output: %s", err, strings.TrimSpace(string(output)))}
+	}
+
+	// Everything is in the final GIF now, so the cache isn't needed anymore
+	if opts.Resume {
+		removeTemp(tempDir)
+	}
+
+	progress.Frames = tracker.totalFrames()
+	progress.FramesProcessed = int64(progress.Frames)
+	if elapsed := time.Since(progress.StartTime).Seconds(); elapsed > 0 {
+		progress.AvgProcessRate = duration / elapsed
+	}
+
+	return nil
+}
+
+// generateSharedPalette builds the palette for the whole clip
+func generateSharedPalette(ctx context.Context, convOpts gifmaker.Options, palettePath string, start, duration float64) error {
+	logger := GetLogger()
+	ffmpegPath := convOpts.FFmpegPath
+
+	paletteArgs := []string{
+		"-y",
+		"-loglevel", "error",
+	}
+	paletteArgs = append(paletteArgs, convOpts.InputArgs()...)
+	paletteArgs = append(paletteArgs,
+		"-i", opts.Input,
+		"-ss", formatSeconds(start),
+		"-t", formatSeconds(duration),
+		"-vf", fmt.Sprintf("%s,%s", convOpts.BaseFilter(), convOpts.PaletteGenFilter()),
+		palettePath,
+	)
+	fmt.Println("Generating shared palette...")
+	logger.Debugf("FFmpeg palette command: %s %s", ffmpegPath, strings.Join(paletteArgs, " "))
+	if output, err := runFFmpeg(ctx, ffmpegPath, paletteArgs...); err != nil {
+		return &conversionError{fmt.Errorf("failed to generate palette: %w\nError output: %s", err, strings.TrimSpace(string(output)))}
+	}
+
+	if opts.Resume {
+		return markArtifactDone(palettePath, 0)
+	}
+	return nil
+}
+
+// convertChunk converts a single time segment using the shared palette
+func convertChunk(ctx context.Context, convOpts gifmaker.Options, palettePath string, c chunk, tracker *parallelProgress) error {
+	logger := GetLogger()
+	ffmpegPath := convOpts.FFmpegPath
+
+	chunkArgs := chunkFFmpegArgs(convOpts, palettePath, c)
+	logger.Debugf("FFmpeg chunk %d command: %s %s", c.Index, ffmpegPath, strings.Join(chunkArgs, " "))
+
+	chunkCmd := exec.CommandContext(ctx, ffmpegPath, chunkArgs...)
+	stdout, err := chunkCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe for chunk %d: %w", c.Index, err)
+	}
+	var errOutput strings.Builder
+	chunkCmd.Stderr = &errOutput
+
+	if err := startFFmpeg(chunkCmd); err != nil {
+		return fmt.Errorf("failed to start FFmpeg for chunk %d: %w", c.Index, err)
+	}
+
+	trackChunkProgress(stdout, c, tracker)
+
+	if err := chunkCmd.Wait(); err != nil {
+		return &conversionError{fmt.Errorf("FFmpeg failed on chunk %d: %w\nError output: %s", c.Index, err, strings.TrimSpace(errOutput.String()))}
+	}
+
+	return nil
+}
+
+// chunkFFmpegArgs builds the FFmpeg arguments that convert one chunk
+func chunkFFmpegArgs(convOpts gifmaker.Options, palettePath string, c chunk) []string {
+	filter := fmt.Sprintf("[0:v]%s[x];[x][1:v]%s", convOpts.BaseFilter(), convOpts.PaletteUseFilter())
+	chunkArgs := []string{
+		"-y",
+		"-loglevel", "error",
+		"-progress", "pipe:1",
+	}
+	seekArgs := []string{"-ss", formatSeconds(c.Start)}
+	if convOpts.AccurateSeek {
+		if coarse, fine := gifmaker.SplitSeek(c.Start); coarse > 0 {
+			chunkArgs = append(chunkArgs, "-ss", formatSeconds(coarse))
+			seekArgs = []string{"-ss", formatSeconds(fine)}
+		}
+	}
+	if convOpts.FastSeek {
+		chunkArgs = append(chunkArgs, seekArgs...)
+	}
+	chunkArgs = append(chunkArgs, convOpts.InputArgs()...)
+	chunkArgs = append(chunkArgs, "-i", opts.Input, "-i", palettePath)
+	if !convOpts.FastSeek {
+		chunkArgs = append(chunkArgs, seekArgs...)
+	}
+	chunkArgs = append(chunkArgs,
+		"-t", formatSeconds(c.Duration),
+		"-filter_complex", filter,
+		"-threads", strconv.Itoa(chunkThreads(convOpts.Threads, opts.Parallel)),
+	)
+	chunkArgs = append(chunkArgs, convOpts.FPSModeArgs()...)
+	chunkArgs = append(chunkArgs, c.Output)
+
+	return chunkArgs
+}
+
+// trackChunkProgress reads a worker's -progress stream until it ends
+func trackChunkProgress(r io.Reader, c chunk, tracker *parallelProgress) {
+	var progress gifmaker.Progress
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if !gifmaker.ParseProgressLine(scanner.Text(), &progress) {
+			continue
+		}
+		// The last report can stop short of the end of the chunk
+		if progress.Done {
+			progress.CurrentTime = c.Duration
+		}
+		tracker.update(c.Index, progress.CurrentTime, progress.FramesProcessed)
+	}
+}
+
+// chunkThreads splits the FFmpeg thread budget between the workers, so
+// running them side by side doesn't oversubscribe the CPU. A budget of 0
+// means FFmpeg would pick its own count, which is one per core.
+func chunkThreads(threads, workers int) int {
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return max(1, threads/workers)
+}
+
+// formatSeconds formats seconds for use as an FFmpeg time argument
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
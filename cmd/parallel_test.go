@@ -0,0 +1,109 @@
+// cmd/parallel_test.go
+package cmd
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+func TestTrackChunkProgress(t *testing.T) {
+	stream := strings.Join([]string{
+		"frame=12",
+		"out_time_us=500000",
+		"out_time_ms=500000",
+		"speed=N/A",
+		"progress=continue",
+		"frame=30",
+		"out_time_us=1200000",
+		"out_time_ms=1200000",
+		"speed=1.2x",
+		"progress=end",
+	}, "\n")
+
+	tracker := &parallelProgress{
+		times:  make([]float64, 2),
+		frames: make([]int64, 2),
+	}
+	trackChunkProgress(strings.NewReader(stream), chunk{Index: 1, Duration: 1.25}, tracker)
+
+	// The end of the stream completes the chunk even if the last report
+	// stops short of it
+	if tracker.times[1] != 1.25 {
+		t.Errorf("chunk time = %g, want 1.25", tracker.times[1])
+	}
+	if got := tracker.chunkFrames(1); got != 30 {
+		t.Errorf("chunk frames = %d, want 30", got)
+	}
+	if tracker.times[0] != 0 || tracker.frames[0] != 0 {
+		t.Errorf("other chunk changed: time %g, frames %d", tracker.times[0], tracker.frames[0])
+	}
+}
+
+func TestChunkThreads(t *testing.T) {
+	tests := []struct {
+		threads, workers int
+		want             int
+	}{
+		{8, 4, 2},
+		{8, 3, 2},
+		{2, 4, 1},
+		{6, 1, 6},
+		{6, 0, 6},
+		{0, 1, runtime.NumCPU()},
+	}
+	for _, tt := range tests {
+		if got := chunkThreads(tt.threads, tt.workers); got != tt.want {
+			t.Errorf("chunkThreads(%d, %d) = %d, want %d", tt.threads, tt.workers, got, tt.want)
+		}
+	}
+}
+
+func TestChunkFFmpegArgsSeek(t *testing.T) {
+	saved := opts
+	t.Cleanup(func() { opts = saved })
+	opts.Input = "in.mp4"
+	opts.Parallel = 2
+
+	tests := []struct {
+		name          string
+		start         float64
+		accurate      bool
+		fast          bool
+		input, output []string
+	}{
+		{"start 0", 0, true, false, nil, []string{"0.000"}},
+		{"below the margin", 1.5, true, false, nil, []string{"1.500"}},
+		{"exactly the margin", 2, true, false, []string{"1.000"}, []string{"1.000"}},
+		{"large start", 3723.25, true, false, []string{"3722.000"}, []string{"1.250"}},
+		{"output seek", 3723.25, false, false, nil, []string{"3723.250"}},
+		{"fast seek", 3723.25, false, true, []string{"3723.250"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convOpts := gifmaker.Options{Input: opts.Input, FPS: 10, Quality: 90, AccurateSeek: tt.accurate, FastSeek: tt.fast}
+			c := chunk{Index: 1, Start: tt.start, Duration: 5, Output: "chunk-001.gif"}
+			args := chunkFFmpegArgs(convOpts, "palette.png", c)
+
+			// Everything after the first -i is an output-side seek
+			first := slices.Index(args, "-i")
+			var input, output []string
+			for i, arg := range args[:len(args)-1] {
+				if arg != "-ss" {
+					continue
+				}
+				if i < first {
+					input = append(input, args[i+1])
+				} else {
+					output = append(output, args[i+1])
+				}
+			}
+			if !slices.Equal(input, tt.input) || !slices.Equal(output, tt.output) {
+				t.Errorf("seeks %v before the input and %v after, want %v and %v", input, output, tt.input, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// cmd/paths.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// envVarRegex matches $VAR and ${VAR} references in a path
+var envVarRegex = regexp.MustCompile(`\$\{(\w+)\}|\$(\w+)`)
+
+// expandPath expands environment variables ($VAR or ${VAR}) and a leading ~
+// in a path. Shells usually do this, but not for paths typed at an
+// interactive prompt or quoted on the command line. Unset variables are
+// left as they are.
+func expandPath(path string) string {
+	path = envVarRegex.ReplaceAllStringFunc(path, func(ref string) string {
+		name := strings.Trim(ref, "${}")
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		return ref
+	})
+
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		GetLogger().Warnf("Could not expand %s: %v", path, err)
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// ensureOutputDir creates the directory an output file will be written to
+// if it doesn't exist yet
+func ensureOutputDir(output string) error {
+	dir := filepath.Dir(output)
+	if info, err := os.Stat(dir); err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("cannot write output to %s: %s is not a directory", output, dir)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+	GetLogger().Infof("Created output directory %s", dir)
+	return nil
+}
+
+// prepareTempDir makes sure a --temp-dir can hold temporary files,
+// creating it if needed, and returns its expanded path
+func prepareTempDir(dir string) (string, error) {
+	dir = expandPath(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory %s: %w", dir, err)
+	}
+
+	// MkdirAll succeeds on existing directories we can't write to
+	probe, err := os.CreateTemp(dir, ".gif-maker-probe-*")
+	if err != nil {
+		return "", fmt.Errorf("temp directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	GetLogger().Infof("Using %s for temporary files", dir)
+	return dir, nil
+}
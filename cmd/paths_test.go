@@ -0,0 +1,73 @@
+// cmd/paths_test.go
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GIF_MAKER_TEST_DIR", "/videos")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"home", "~", home},
+		{"under home", "~/sub/clip.mp4", filepath.Join(home, "sub", "clip.mp4")},
+		{"variable", "$GIF_MAKER_TEST_DIR/clip.mp4", "/videos/clip.mp4"},
+		{"braced variable", "${GIF_MAKER_TEST_DIR}/clip.mp4", "/videos/clip.mp4"},
+		{"variable inside a name", "${GIF_MAKER_TEST_DIR}2/clip.mp4", "/videos2/clip.mp4"},
+		{"unset variable", "$GIF_MAKER_TEST_UNSET/clip.mp4", "$GIF_MAKER_TEST_UNSET/clip.mp4"},
+		{"tilde inside a name", "clips/~old.mp4", "clips/~old.mp4"},
+		{"other user's home", "~someone/clip.mp4", "~someone/clip.mp4"},
+		{"plain", "clip.mp4", "clip.mp4"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandPath(tt.path); got != tt.want {
+				t.Errorf("expandPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureOutputDir(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{"existing directory", filepath.Join(root, "out.gif"), false},
+		{"nested missing directories", filepath.Join(root, "a", "b", "c", "out.gif"), false},
+		{"file where the directory should be", filepath.Join(file, "out.gif"), true},
+		{"file further up the path", filepath.Join(file, "sub", "out.gif"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ensureOutputDir(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ensureOutputDir(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			info, err := os.Stat(filepath.Dir(tt.output))
+			if err != nil || !info.IsDir() {
+				t.Errorf("%s is not a directory after ensureOutputDir (stat error %v)", filepath.Dir(tt.output), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// cmd/poster.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// posterPath returns where the poster for an output goes: next to it, with
+// a .jpg extension
+func posterPath(output string) string {
+	return strings.TrimSuffix(output, filepath.Ext(output)) + ".jpg"
+}
+
+// writePoster saves the first frame of the finished output as a JPEG, for
+// the poster attribute of a <video> embed. The frame is taken from the
+// output rather than the source so it has the same crop, size and colors.
+func writePoster() error {
+	ffmpegPath, err := ffmpegManager.GetPath()
+	if err != nil {
+		return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+	}
+
+	poster := posterPath(opts.Output)
+	if opts.NoOverwrite {
+		if _, err := os.Stat(poster); err == nil {
+			return fmt.Errorf("poster file already exists: %s (remove it or drop --no-overwrite)", poster)
+		}
+	}
+
+	GetLogger().Infof("Writing poster frame: %s", poster)
+	if err := extractFrame(ffmpegPath, opts.Output, "", poster, 0); err != nil {
+		return fmt.Errorf("failed to write poster: %w", err)
+	}
+
+	color.Green("✅ Poster frame saved to %s", poster)
+	return nil
+}
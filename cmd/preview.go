@@ -0,0 +1,134 @@
+// cmd/preview.go
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+)
+
+// Width of the preview frames in pixels
+const previewWidth = 320
+
+// previewFrameTimes returns the start, middle and end of the clip. The end
+// frame is taken slightly early since seeking to the very last timestamp
+// often finds no frame.
+func previewFrameTimes(start, duration float64) []float64 {
+	end := start + duration - 0.1
+	if end < start {
+		end = start
+	}
+	return []float64{start, start + duration/2, end}
+}
+
+// confirmPreview extracts the first, middle and last frame of the selected
+// clip, shows them and asks whether to go ahead. It returns false if the
+// user declined.
+func confirmPreview(ffmpegPath string) (bool, error) {
+	logger := GetLogger()
+
+	totalDuration, _, err := getVideoMetadata(opts.Input, ffmpegPath)
+	if err != nil || totalDuration <= 0 {
+		logger.Warnf("Skipping preview, could not determine video duration: %v", err)
+		return true, nil
+	}
+	start, duration := resolveClipRange(totalDuration)
+	if opts.End != "" {
+		// --end is only turned into a duration when converting
+		if d, err := clipDurationFromEnd(opts.Start, opts.End); err == nil && d < duration {
+			duration = d
+		}
+	}
+	if duration <= 0 {
+		logger.Warn("Skipping preview, the selected clip is empty")
+		return true, nil
+	}
+
+	tempDir, err := os.MkdirTemp(tempRoot, "gif-maker-preview")
+	if err != nil {
+		return false, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTemp(tempDir)
+
+	fmt.Println("Extracting preview frames...")
+	labels := []string{"Start", "Middle", "End"}
+	for i, t := range previewFrameTimes(start, duration) {
+		path := filepath.Join(tempDir, fmt.Sprintf("%d-%s.png", i+1, strings.ToLower(labels[i])))
+		if err := extractFrame(ffmpegPath, opts.Input, formatTimestamp(t), path, previewWidth); err != nil {
+			logger.Warnf("Could not extract preview frame at %s: %v", formatTimestamp(t), err)
+			continue
+		}
+
+		color.New(color.FgHiCyan).Printf("%s (%s):\n", labels[i], formatTimestamp(t))
+		if !showInlineImage(path) {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	proceed := true
+	question := &survey.Confirm{
+		Message: "Proceed with the conversion?",
+		Default: true,
+	}
+	if err := survey.AskOne(question, &proceed); err != nil {
+		return false, err
+	}
+	return proceed, nil
+}
+
+// inlineImageProtocol returns the inline image protocol the terminal
+// supports ("iterm" or "kitty"), or "" if images can't be shown
+func inlineImageProtocol() string {
+	switch {
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app", os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return "iterm"
+	case os.Getenv("KITTY_WINDOW_ID") != "", os.Getenv("TERM") == "xterm-kitty":
+		return "kitty"
+	}
+	return ""
+}
+
+// showInlineImage draws a PNG in the terminal and reports whether it could
+func showInlineImage(path string) bool {
+	protocol := inlineImageProtocol()
+	if protocol == "" {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	switch protocol {
+	case "iterm":
+		fmt.Printf("\033]1337;File=inline=1;size=%d;preserveAspectRatio=1:%s\a\n", len(data), encoded)
+	case "kitty":
+		// Kitty wants the payload in chunks of at most 4096 bytes, with the
+		// image options only on the first one
+		for first := true; len(encoded) > 0; first = false {
+			n := len(encoded)
+			if n > 4096 {
+				n = 4096
+			}
+			more := 0
+			if n < len(encoded) {
+				more = 1
+			}
+			if first {
+				fmt.Printf("\033_Ga=T,f=100,m=%d;%s\033\\", more, encoded[:n])
+			} else {
+				fmt.Printf("\033_Gm=%d;%s\033\\", more, encoded[:n])
+			}
+			encoded = encoded[n:]
+		}
+		fmt.Println()
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+// cmd/probe.go
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// VideoInfo is what ffprobe reports about the first video stream of a
+// file. Anything it couldn't report is left at zero.
+type VideoInfo struct {
+	Width     int
+	Height    int
+	Duration  float64 // Seconds
+	FrameRate float64 // Frames per second
+	Codec     string  // Empty if the file has no video stream
+
+	PixelFormat    string
+	ColorRange     string // tv (limited) or pc (full)
+	ColorTransfer  string // e.g. bt709, or smpte2084 and arib-std-b67 for HDR
+	ColorPrimaries string
+
+	// Rotation is how many degrees clockwise the video has to be turned to
+	// display upright, a multiple of 90
+	Rotation int
+
+	// AlphaMode is set for VP8/VP9 streams that carry alpha as side data
+	AlphaMode bool
+}
+
+// HasVideo reports whether ffprobe found a video stream
+func (v *VideoInfo) HasVideo() bool {
+	return v.Codec != ""
+}
+
+// ProbeVideo uses ffprobe to extract basic information about a video file.
+// Results are cached for the rest of the run until the file changes.
+// inputArgs are passed to ffprobe right before the file.
+func ProbeVideo(videoPath string, inputArgs ...string) (*VideoInfo, error) {
+	stat, err := os.Stat(videoPath)
+	if os.IsNotExist(err) {
+		return nil, &inputNotFoundError{Kind: "video file", Path: videoPath}
+	}
+
+	var key probeKey
+	if err == nil {
+		key = newProbeKey(videoPath, stat)
+		if video, ok := cachedProbe(key); ok {
+			return video, nil
+		}
+	}
+
+	info, err := runFFprobe(videoPath, inputArgs...)
+	if err != nil {
+		return nil, err
+	}
+	video := parseVideoInfo(info)
+	if key.path != "" {
+		storeProbe(key, video)
+	}
+	return video, nil
+}
+
+// parseVideoInfo parses the raw ffprobe properties. Values that don't parse
+// are left at zero.
+func parseVideoInfo(info map[string]string) *VideoInfo {
+	video := &VideoInfo{
+		Codec:          info["codec_name"],
+		PixelFormat:    info["pix_fmt"],
+		ColorRange:     probedString(info["color_range"]),
+		ColorTransfer:  probedString(info["color_transfer"]),
+		ColorPrimaries: probedString(info["color_primaries"]),
+		Rotation:       probedRotation(info),
+		AlphaMode:      info["TAG:alpha_mode"] == "1",
+	}
+	video.Width, _ = strconv.Atoi(info["width"])
+	video.Height, _ = strconv.Atoi(info["height"])
+	if d, err := strconv.ParseFloat(info["duration"], 64); err == nil && d > 0 {
+		video.Duration = d
+	}
+	video.FrameRate, _ = parseFrameRate(info["r_frame_rate"])
+	return video
+}
+
+// probedString returns an ffprobe value, or "" where it reports it as
+// unknown
+func probedString(value string) string {
+	if value == "unknown" {
+		return ""
+	}
+	return value
+}
+
+// probeKey identifies a file well enough to reuse what was probed from it;
+// a file that is replaced or rewritten gets a new modification time
+type probeKey struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// probeCache holds the ffprobe results of this run, so the checks and
+// estimates that each need something from the input probe it only once
+var probeCache = struct {
+	sync.Mutex
+	entries map[probeKey]VideoInfo
+}{entries: make(map[probeKey]VideoInfo)}
+
+// newProbeKey returns the cache key for a file from its stat
+func newProbeKey(path string, stat os.FileInfo) probeKey {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return probeKey{path: path, modTime: stat.ModTime(), size: stat.Size()}
+}
+
+// cachedProbe returns a copy of the cached probe result for key, so callers
+// are free to change it
+func cachedProbe(key probeKey) (*VideoInfo, bool) {
+	probeCache.Lock()
+	defer probeCache.Unlock()
+	video, ok := probeCache.entries[key]
+	return &video, ok
+}
+
+// storeProbe caches a probe result
+func storeProbe(key probeKey, video *VideoInfo) {
+	probeCache.Lock()
+	defer probeCache.Unlock()
+	probeCache.entries[key] = *video
+}
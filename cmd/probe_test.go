@@ -0,0 +1,96 @@
+// cmd/probe_test.go
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseVideoInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   VideoInfo
+	}{
+		{
+			name: "landscape H.264",
+			output: `codec_name=h264
+width=1920
+height=1080
+pix_fmt=yuv420p
+color_range=tv
+color_transfer=bt709
+color_primaries=bt709
+r_frame_rate=30000/1001
+duration=12.345000
+`,
+			want: VideoInfo{Width: 1920, Height: 1080, Duration: 12.345, FrameRate: 30000.0 / 1001, Codec: "h264",
+				PixelFormat: "yuv420p", ColorRange: "tv", ColorTransfer: "bt709", ColorPrimaries: "bt709"},
+		},
+		{
+			name: "phone video with rotation side data",
+			output: `codec_name=hevc
+width=1920
+height=1080
+pix_fmt=yuv420p10le
+color_range=tv
+color_transfer=arib-std-b67
+color_primaries=bt2020
+r_frame_rate=30/1
+duration=5.000000
+rotation=-90
+`,
+			want: VideoInfo{Width: 1920, Height: 1080, Duration: 5, FrameRate: 30, Codec: "hevc",
+				PixelFormat: "yuv420p10le", ColorRange: "tv", ColorTransfer: "arib-std-b67", ColorPrimaries: "bt2020", Rotation: 90},
+		},
+		{
+			name: "older rotate tag",
+			output: `codec_name=h264
+width=1280
+height=720
+r_frame_rate=25/1
+TAG:rotate=270
+`,
+			want: VideoInfo{Width: 1280, Height: 720, FrameRate: 25, Codec: "h264", Rotation: 270},
+		},
+		{
+			name: "missing frame rate and duration",
+			output: `codec_name=vp9
+width=640
+height=360
+pix_fmt=yuva420p
+color_range=unknown
+r_frame_rate=0/0
+duration=N/A
+TAG:alpha_mode=1
+`,
+			want: VideoInfo{Width: 640, Height: 360, Codec: "vp9", PixelFormat: "yuva420p", AlphaMode: true},
+		},
+		{
+			name:   "no frame rate line at all",
+			output: "codec_name=mjpeg\nwidth=320\nheight=240\n",
+			want:   VideoInfo{Width: 320, Height: 240, Codec: "mjpeg"},
+		},
+		{
+			name:   "no video stream",
+			output: "",
+			want:   VideoInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseVideoInfo(parseFFprobeOutput(tt.output))
+			// Frame rates are fractions, so compare them apart
+			if math.Abs(got.FrameRate-tt.want.FrameRate) > 1e-9 {
+				t.Errorf("FrameRate = %g, want %g", got.FrameRate, tt.want.FrameRate)
+			}
+			got.FrameRate, tt.want.FrameRate = 0, 0
+			if *got != tt.want {
+				t.Errorf("parseVideoInfo() = %+v, want %+v", *got, tt.want)
+			}
+			if got.HasVideo() != (tt.want.Codec != "") {
+				t.Errorf("HasVideo() = %v, want %v", got.HasVideo(), tt.want.Codec != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// cmd/progressjson.go
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Progress output formats
+const (
+	progressFormatText = "text"
+	progressFormatJSON = "json"
+)
+
+// progressEvent is one line of --progress-format json output. Fields that
+// can't be known yet are null.
+type progressEvent struct {
+	Percent     *float64 `json:"percent"`      // 0-100
+	CurrentTime float64  `json:"current_time"` // Seconds of the clip converted
+	Total       *float64 `json:"total"`        // Length of the clip in seconds
+	FPS         float64  `json:"fps"`          // Frames converted per second
+	ETA         *float64 `json:"eta"`          // Seconds left
+	Size        int64    `json:"size"`         // Bytes written so far
+	Frames      int64    `json:"frames"`
+	Done        bool     `json:"done"`
+}
+
+// newProgressEvent builds an event from a progress update. rate is the
+// smoothed processing speed and elapsed the wall time so far.
+func newProgressEvent(update gifmaker.Progress, totalDuration, rate float64, elapsed time.Duration) progressEvent {
+	event := progressEvent{
+		CurrentTime: update.CurrentTime,
+		Size:        sizeInBytes(update.CurrentSize, update.SizeUnit),
+		Frames:      update.FramesProcessed,
+		Done:        update.Done,
+	}
+	if totalDuration <= 0 {
+		totalDuration = update.TotalDuration
+	}
+
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		event.FPS = math.Round(float64(update.FramesProcessed)/seconds*100) / 100
+	}
+
+	if totalDuration > 0 {
+		current := math.Min(update.CurrentTime, totalDuration)
+		if update.Done {
+			current = totalDuration
+		}
+		percent := math.Round(current/totalDuration*1000) / 10
+		event.Total = &totalDuration
+		event.Percent = &percent
+
+		if rate > 0 {
+			eta := math.Ceil((totalDuration - current) / rate)
+			event.ETA = &eta
+		}
+	}
+	return event
+}
+
+// jsonProgressReporter returns a progress callback that writes each update
+// to w as a line of JSON, for tools that draw their own progress display
+func jsonProgressReporter(w io.Writer, totalDuration float64) func(gifmaker.Progress) {
+	encoder := json.NewEncoder(w)
+	start := time.Now()
+	var rate float64
+	return func(update gifmaker.Progress) {
+		if update.ProcessingRate > 0 {
+			rate = smoothRate(rate, update.ProcessingRate)
+		}
+		encoder.Encode(newProgressEvent(update, totalDuration, rate, time.Since(start)))
+	}
+}
@@ -0,0 +1,129 @@
+// cmd/qualitysample.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Interactive quality levels and the --quality each one stands for
+var interactiveQualities = []struct {
+	Name    string
+	Label   string
+	Quality int
+}{
+	{"Low", "Low (faster, smaller file)", 50},
+	{"Medium", "Medium", 75},
+	{"High", "High (slower, larger file)", 95},
+}
+
+// Length of the piece of the clip the quality samples are made from
+const qualitySampleSeconds = 2.0
+
+// promptQualitySamples offers to make samples at each quality level and
+// adds their sizes to the quality options. Failing to make them isn't
+// fatal; the options are just left as they are.
+func promptQualitySamples(total float64, qualityOptions []string) error {
+	var sample bool
+	sampleQuestion := &survey.Confirm{
+		Message: fmt.Sprintf("Make %gs samples to compare the file size at each quality?", qualitySampleSeconds),
+		Default: true,
+	}
+	if err := survey.AskOne(sampleQuestion, &sample); err != nil {
+		return err
+	}
+	if !sample {
+		return nil
+	}
+
+	fmt.Println("Converting samples...")
+	sizes, length, err := sampleQualitySizes(total)
+	if err != nil {
+		warning := fmt.Sprintf("Could not make quality samples: %v", err)
+		color.Yellow("⚠️ %s", warning)
+		GetLogger().Warn(warning)
+		return nil
+	}
+
+	summary := make([]string, len(sizes))
+	for i, size := range sizes {
+		summary[i] = fmt.Sprintf("%s: %s", interactiveQualities[i].Name, HumanizeBytes(size))
+		qualityOptions[i] = fmt.Sprintf("%s, %s per %.1fs", interactiveQualities[i].Label, HumanizeBytes(size), length)
+	}
+	fmt.Printf("%s for this %.1fs sample\n", strings.Join(summary, ", "), length)
+	return nil
+}
+
+// sampleQualitySizes converts a short piece from the middle of the clip at
+// every interactive quality level, with the fps and width chosen so far,
+// and returns the size of each GIF along with the length of the piece
+func sampleQualitySizes(total float64) ([]int64, float64, error) {
+	if err := checkFFmpegInstallation(); err != nil {
+		return nil, 0, err
+	}
+	ffmpegPath, err := ffmpegManager.GetPath()
+	if err != nil {
+		return nil, 0, &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+	}
+
+	start, duration := resolveClipRange(total)
+	if opts.End != "" {
+		if d, err := clipDurationFromEnd(opts.Start, opts.End); err == nil {
+			duration = d
+		}
+	}
+	length := min(duration, qualitySampleSeconds)
+	if length <= 0 {
+		return nil, 0, fmt.Errorf("the clip is empty")
+	}
+	start += (duration - length) / 2
+
+	fps := opts.FPS
+	if opts.fpsAuto {
+		video, err := ProbeVideo(opts.Input)
+		if err != nil {
+			return nil, 0, err
+		}
+		fps = autoFPS(video.FrameRate)
+	}
+
+	tempDir, err := os.MkdirTemp(tempRoot, "gif-maker-samples")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTemp(tempDir)
+
+	sizes := make([]int64, len(interactiveQualities))
+	for i, level := range interactiveQualities {
+		output := filepath.Join(tempDir, fmt.Sprintf("sample-%d.gif", level.Quality))
+		sampleOpts := gifmaker.Options{
+			FFmpegPath: ffmpegPath,
+			Input:      opts.Input,
+			Output:     output,
+			Start:      formatTimestamp(start),
+			Duration:   formatTimestamp(length),
+			FastSeek:   true,
+			FPS:        fps,
+			Width:      opts.Width,
+			Quality:    level.Quality,
+			LogLevel:   "error",
+		}
+		if _, err := gifmaker.Convert(context.Background(), sampleOpts, nil); err != nil {
+			return nil, 0, err
+		}
+		sample, err := statOutput(output, "")
+		if err != nil {
+			return nil, 0, err
+		}
+		sizes[i] = sample.Size
+	}
+	return sizes, length, nil
+}
@@ -0,0 +1,40 @@
+// cmd/ranges.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// checkRanges makes sure every range is within the video and reports what
+// will be joined. totalDuration is 0 if it isn't known.
+func checkRanges(ranges []gifmaker.TimeRange, totalDuration float64) error {
+	if totalDuration > 0 {
+		for _, r := range ranges {
+			if r.Start >= totalDuration {
+				return usageErrorf("range %s starts after the end of the video (%.2f seconds)", r, totalDuration)
+			}
+			if r.End > totalDuration {
+				return usageErrorf("range %s ends after the end of the video (%.2f seconds)", r, totalDuration)
+			}
+		}
+	} else {
+		GetLogger().Warn("Could not determine the video duration, not checking --ranges against it")
+	}
+
+	total := gifmaker.RangesDuration(ranges)
+	GetLogger().Infof("Joining %d ranges (%s), %.2f seconds in total", len(ranges), formatRanges(ranges), total)
+	fmt.Printf("Joining %d ranges into a %.1fs clip\n", len(ranges), total)
+	return nil
+}
+
+// formatRanges lists the ranges for messages
+func formatRanges(ranges []gifmaker.TimeRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ", ")
+}
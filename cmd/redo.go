@@ -0,0 +1,120 @@
+// cmd/redo.go
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// promptRedo asks whether to adjust the frame rate, width and quality and
+// convert the same clip again. prev holds the options the last conversion
+// started from; they are the defaults for the prompts and, if the user
+// goes ahead, everything not asked about is restored from them.
+func promptRedo(prev ConvertOptions) (bool, error) {
+	redo := false
+	question := &survey.Confirm{
+		Message: "Not happy? Adjust and re-run?",
+		Default: false,
+	}
+	if err := survey.AskOne(question, &redo); err != nil {
+		return false, err
+	}
+	if !redo {
+		return false, nil
+	}
+
+	// Converting resolves some options (auto settings, auto trim), so
+	// start over from the previous ones
+	opts = prev
+
+	fpsDefault := strconv.Itoa(prev.FPS)
+	if prev.fpsAuto {
+		fpsDefault = "auto"
+	}
+	var fpsStr string
+	fpsQuestion := &survey.Input{
+		Message: "Frames per second (auto = match source):",
+		Default: fpsDefault,
+	}
+	validateFPS := func(ans interface{}) error {
+		_, _, err := parseFPS(ans.(string))
+		return err
+	}
+	if err := survey.AskOne(fpsQuestion, &fpsStr, survey.WithValidator(validateFPS)); err != nil {
+		return false, err
+	}
+	if err := setFPS(fpsStr); err != nil {
+		return false, err
+	}
+
+	widthDefault := ""
+	if prev.Width > 0 {
+		widthDefault = strconv.Itoa(prev.Width)
+	}
+	var widthStr string
+	widthQuestion := &survey.Input{
+		Message: "Width in pixels (leave empty to keep original size):",
+		Default: widthDefault,
+	}
+	validateWidth := func(ans interface{}) error {
+		_, err := parseRedoWidth(ans.(string))
+		return err
+	}
+	if err := survey.AskOne(widthQuestion, &widthStr, survey.WithValidator(validateWidth)); err != nil {
+		return false, err
+	}
+	width, err := parseRedoWidth(widthStr)
+	if err != nil {
+		return false, err
+	}
+	opts.Width = width
+
+	var qualityStr string
+	qualityQuestion := &survey.Input{
+		Message: "Quality (1-100, higher = better but larger file):",
+		Default: strconv.Itoa(prev.Quality),
+	}
+	validateQuality := func(ans interface{}) error {
+		_, err := parseRedoQuality(ans.(string))
+		return err
+	}
+	if err := survey.AskOne(qualityQuestion, &qualityStr, survey.WithValidator(validateQuality)); err != nil {
+		return false, err
+	}
+	quality, err := parseRedoQuality(qualityStr)
+	if err != nil {
+		return false, err
+	}
+	opts.Quality = quality
+
+	// The re-run replaces the output it made last time
+	opts.NoOverwrite = false
+
+	return true, nil
+}
+
+// parseRedoWidth parses the width answer, where empty keeps the original
+// size and gives 0
+func parseRedoWidth(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	width, err := strconv.Atoi(value)
+	if err != nil || width < 1 {
+		return 0, fmt.Errorf("invalid width value: %s", value)
+	}
+	return width, nil
+}
+
+// parseRedoQuality parses the quality answer
+func parseRedoQuality(value string) (int, error) {
+	quality, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || quality < 1 || quality > 100 {
+		return 0, fmt.Errorf("invalid quality value: %s (expected 1-100)", value)
+	}
+	return quality, nil
+}
@@ -0,0 +1,66 @@
+// cmd/redo_test.go
+package cmd
+
+import "testing"
+
+func TestParseRedoAnswers(t *testing.T) {
+	widths := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"  ", 0, false},
+		{"480", 480, false},
+		{" 320 ", 320, false},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"wide", 0, true},
+	}
+	for _, tt := range widths {
+		got, err := parseRedoWidth(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseRedoWidth(%q) = %d, %v, want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	qualities := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"90", 90, false},
+		{" 1 ", 1, false},
+		{"100", 100, false},
+		{"0", 0, true},
+		{"101", 0, true},
+		{"", 0, true},
+		{"high", 0, true},
+	}
+	for _, tt := range qualities {
+		got, err := parseRedoQuality(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseRedoQuality(%q) = %d, %v, want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	fpsValues := []struct {
+		value   string
+		fps     int
+		auto    bool
+		wantErr bool
+	}{
+		{"15", 15, false, false},
+		{"auto", 0, true, false},
+		{" AUTO ", 0, true, false},
+		{"0", 0, true, false},
+		{"-1", 0, false, true},
+		{"fast", 0, false, true},
+	}
+	for _, tt := range fpsValues {
+		fps, auto, err := parseFPS(tt.value)
+		if fps != tt.fps || auto != tt.auto || (err != nil) != tt.wantErr {
+			t.Errorf("parseFPS(%q) = %d, %v, %v, want %d, %v, error %v", tt.value, fps, auto, err, tt.fps, tt.auto, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// cmd/result.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// ConversionResult describes a finished conversion. The conversion itself
+// only produces it; printing the summary is left to the caller.
+type ConversionResult struct {
+	Outputs     []ConversionOutput
+	Width       int // Source frame size, as the summary has always shown it
+	Height      int
+	Frames      int // Frames in the (first) output
+	Elapsed     time.Duration
+	ProcessRate float64 // Average processing speed relative to real time, 0 if not measured
+
+	// FrameInterval is the time between the tiles of a contact sheet
+	FrameInterval float64
+}
+
+// ConversionOutput is one file a conversion wrote
+type ConversionOutput struct {
+	Path  string
+	Size  int64  // Bytes
+	Label string // Tells several outputs apart in the summary, e.g. "480px"
+}
+
+// SizeMB returns the output size in megabytes
+func (o ConversionOutput) SizeMB() float64 {
+	return float64(o.Size) / 1024 / 1024
+}
+
+// statOutput records the size of a written output
+func statOutput(path, label string) (ConversionOutput, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return ConversionOutput{}, fmt.Errorf("failed to get output file info: %w", err)
+	}
+	return ConversionOutput{Path: path, Size: fileInfo.Size(), Label: label}, nil
+}
+
+// newConversionResult collects the outputs of a finished conversion along
+// with what progress tracking learned about it
+func newConversionResult(progress *ProgressData, elapsed time.Duration) (*ConversionResult, error) {
+	result := &ConversionResult{
+		Width:       progress.Width,
+		Height:      progress.Height,
+		Frames:      progress.Frames,
+		Elapsed:     elapsed,
+		ProcessRate: progress.AvgProcessRate,
+	}
+	for i, path := range conversionOutputs() {
+		label := ""
+		if len(opts.Sizes) > 0 {
+			label = fmt.Sprintf("%dpx", opts.Sizes[i])
+		}
+		output, err := statOutput(path, label)
+		if err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	return result, nil
+}
+
+// printConversionResult prints the summary that suits how the conversion
+// was run
+func printConversionResult(result *ConversionResult) {
+	switch {
+	case opts.ContactSheet:
+		printContactSheetSummary(result)
+	case opts.Segment > 0 || opts.ByChapters:
+		printSegmentSummary(result)
+	default:
+		printConversionSummary(result)
+	}
+}
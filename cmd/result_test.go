@@ -0,0 +1,80 @@
+// cmd/result_test.go
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewConversionResult(t *testing.T) {
+	saved := opts
+	t.Cleanup(func() { opts = saved })
+
+	dir := t.TempDir()
+	progress := &ProgressData{Width: 1280, Height: 720, Frames: 42, AvgProcessRate: 1.5}
+
+	t.Run("single output", func(t *testing.T) {
+		opts = ConvertOptions{Output: filepath.Join(dir, "out.gif"), Format: "gif"}
+		if err := os.WriteFile(opts.Output, make([]byte, 2048), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := newConversionResult(progress, 3*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Width != 1280 || result.Height != 720 || result.Frames != 42 || result.ProcessRate != 1.5 || result.Elapsed != 3*time.Second {
+			t.Errorf("result = %+v, want the progress data and elapsed time", result)
+		}
+		if len(result.Outputs) != 1 {
+			t.Fatalf("got %d outputs, want 1", len(result.Outputs))
+		}
+		want := ConversionOutput{Path: opts.Output, Size: 2048}
+		if result.Outputs[0] != want {
+			t.Errorf("output = %+v, want %+v", result.Outputs[0], want)
+		}
+	})
+
+	t.Run("one output per size", func(t *testing.T) {
+		opts = ConvertOptions{Output: filepath.Join(dir, "multi.gif"), Format: "gif", Sizes: []int{480, 240}}
+		for i, name := range []string{"multi-480.gif", "multi-240.gif"} {
+			if err := os.WriteFile(filepath.Join(dir, name), make([]byte, 1000*(i+1)), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, err := newConversionResult(progress, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []ConversionOutput{
+			{Path: filepath.Join(dir, "multi-480.gif"), Size: 1000, Label: "480px"},
+			{Path: filepath.Join(dir, "multi-240.gif"), Size: 2000, Label: "240px"},
+		}
+		if len(result.Outputs) != len(want) {
+			t.Fatalf("got %d outputs, want %d", len(result.Outputs), len(want))
+		}
+		for i := range want {
+			if result.Outputs[i] != want[i] {
+				t.Errorf("output %d = %+v, want %+v", i, result.Outputs[i], want[i])
+			}
+		}
+	})
+
+	t.Run("missing output", func(t *testing.T) {
+		opts = ConvertOptions{Output: filepath.Join(dir, "missing.gif"), Format: "gif"}
+		_, err := newConversionResult(progress, time.Second)
+		if err == nil || !strings.Contains(err.Error(), "failed to get output file info") {
+			t.Errorf("error = %v, want one about the missing output", err)
+		}
+	})
+}
+
+func TestConversionOutputSizeMB(t *testing.T) {
+	if got := (ConversionOutput{Size: 3 * 1024 * 1024 / 2}).SizeMB(); got != 1.5 {
+		t.Errorf("SizeMB() = %g, want 1.5", got)
+	}
+}
@@ -0,0 +1,114 @@
+// cmd/resume.go
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Intermediate files of runs that were never resumed are removed after this
+const resumeCacheMaxAge = 7 * 24 * time.Hour
+
+// resumeKey identifies the intermediate artifacts of a conversion. It
+// changes whenever the input file or any option that affects the output
+// changes, so artifacts from a different setup are never reused.
+func resumeKey(convOpts gifmaker.Options, chunks int) (string, error) {
+	input, err := filepath.Abs(convOpts.Input)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(input)
+	if err != nil {
+		return "", err
+	}
+
+	// Settings that only affect how the work is done, not the result
+	convOpts.FFmpegPath = ""
+	convOpts.Output = ""
+	convOpts.Threads = 0
+	convOpts.HWAccel = ""
+	convOpts.NoOverwrite = false
+
+	settings, err := json.Marshal(struct {
+		Input   string
+		Size    int64
+		ModTime int64
+		Chunks  int
+		Options gifmaker.Options
+	}{input, info.Size(), info.ModTime().UnixNano(), chunks, convOpts})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(settings)
+	return hex.EncodeToString(sum[:12]), nil
+}
+
+// resumeDir returns the cache directory for the artifacts with the given
+// key, creating it if needed
+func resumeDir(key string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find cache directory: %w", err)
+	}
+	root := filepath.Join(cacheDir, "gif-maker", "resume")
+	pruneResumeCache(root)
+
+	dir := filepath.Join(root, key)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create resume directory: %w", err)
+	}
+	return dir, nil
+}
+
+// pruneResumeCache removes leftovers of abandoned runs, including ones whose
+// key changed because the input or options did
+func pruneResumeCache(root string) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < resumeCacheMaxAge {
+			continue
+		}
+		GetLogger().Debugf("Removing stale resume directory %s", entry.Name())
+		os.RemoveAll(filepath.Join(root, entry.Name()))
+	}
+}
+
+// artifactDone reports whether an artifact was completed by an earlier run,
+// along with the frame count recorded for it. A marker file is written only
+// after FFmpeg succeeds, so interrupted artifacts are never reused.
+func artifactDone(path string) (int64, bool) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, false
+	}
+	data, err := os.ReadFile(path + ".done")
+	if err != nil {
+		return 0, false
+	}
+	frames, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return frames, true
+}
+
+// markArtifactDone records that an artifact is complete
+func markArtifactDone(path string, frames int64) error {
+	if err := os.WriteFile(path+".done", []byte(strconv.FormatInt(frames, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to record resume state for %s: %w", path, err)
+	}
+	return nil
+}
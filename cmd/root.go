@@ -3,18 +3,31 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var (
-	verbose bool
-	logger  *logrus.Logger
+	verbose    bool
+	logLevel   string
+	logFormat  string
+	logStderr  bool
+	extractDir string
+	tempRoot   string
+	keepTemp   bool
+	noColor    bool
+	logger     *logrus.Logger
 )
 
+// logDirEnvVar overrides the directory log files are written to
+const logDirEnvVar = "GIF_MAKER_LOG_DIR"
+
 var rootCmd = &cobra.Command{
 	Use:   "gif-maker",
 	Short: "Convert videos to GIFs with customizable options",
@@ -25,23 +38,71 @@ Features:
 - Customizable quality, size, and frame rate
 - Simple command-line interface
 - Progress tracking and logging`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Config files fill in flags before anything reads them, including
+		// the logging and color flags
+		configSources, configWarnings, err := applyConfigFiles(cmd)
+		if err != nil {
+			return err
+		}
+		setupColor()
 		setupLogging()
+		reportConfigSources(configSources, configWarnings)
+		if tempRoot != "" {
+			dir, err := prepareTempDir(tempRoot)
+			if err != nil {
+				return err
+			}
+			tempRoot = dir
+			ffmpegManager.SetTempDir(tempRoot)
+		}
+		if extractDir != "" {
+			ffmpegManager.SetExtractDir(extractDir)
+		}
+		return nil
 	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && cmd == rootCmd {
+		// The root command only fails on unknown subcommands
+		err = asUsageError(err)
+	}
+	cleanupFFmpeg()
+	printRetainedTemp()
+	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
+	// Bad flags are usage errors
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return asUsageError(err)
+	})
+
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level (trace, debug, info, warn, error); overrides --verbose")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format (text, json)")
+	rootCmd.PersistentFlags().BoolVar(&logStderr, "log-stderr", false, "Also write logs to stderr")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().StringVar(&extractDir, "extract-dir", "", "Directory to extract the embedded FFmpeg into (default: a new temp directory)")
+	rootCmd.PersistentFlags().StringVar(&tempRoot, "temp-dir", "", "Directory for temporary files and the extracted FFmpeg (default: the OS temp directory)")
+	rootCmd.PersistentFlags().BoolVar(&keepTemp, "keep-temp", false, "Keep temporary files (palettes, chunks, downloads, the extracted FFmpeg) and print where they are")
 	logger = logrus.New()
 }
 
+// setupColor turns off colored output when asked to with --no-color or
+// the NO_COLOR convention (https://no-color.org)
+func setupColor() {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+		core.DisableColor = true
+	}
+}
+
 func setupLogging() {
 	if verbose {
 		logger.SetLevel(logrus.DebugLevel)
@@ -49,21 +110,56 @@ func setupLogging() {
 		logger.SetLevel(logrus.InfoLevel)
 	}
 
+	// An explicit log level takes precedence over --verbose
+	if logLevel != "" {
+		level, err := logrus.ParseLevel(logLevel)
+		if err != nil {
+			fmt.Printf("Warning: Invalid log level %q, using %s\n", logLevel, logger.GetLevel())
+		} else {
+			logger.SetLevel(level)
+		}
+	}
+
+	switch logFormat {
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	case "text", "":
+		logger.SetFormatter(&logrus.TextFormatter{DisableColors: color.NoColor})
+	default:
+		fmt.Printf("Warning: Invalid log format %q, using text\n", logFormat)
+		logger.SetFormatter(&logrus.TextFormatter{DisableColors: color.NoColor})
+	}
+
 	// Set up log file
-	logDir := filepath.Join(os.TempDir(), "gif-maker-logs")
+	logDir := os.Getenv(logDirEnvVar)
+	if logDir == "" {
+		logDir = filepath.Join(os.TempDir(), "gif-maker-logs")
+	}
+
+	var logFileWriter io.Writer
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		fmt.Printf("Warning: Could not create log directory: %v\n", err)
-		return
+	} else {
+		logFile := filepath.Join(logDir, "gif-maker.log")
+		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Printf("Warning: Could not set up log file: %v\n", err)
+		} else {
+			logFileWriter = f
+		}
 	}
 
-	logFile := filepath.Join(logDir, "gif-maker.log")
-	f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
-	if err != nil {
-		fmt.Printf("Warning: Could not set up log file: %v\n", err)
+	switch {
+	case logFileWriter != nil && logStderr:
+		logger.SetOutput(io.MultiWriter(logFileWriter, os.Stderr))
+	case logFileWriter != nil:
+		logger.SetOutput(logFileWriter)
+	case logStderr:
+		logger.SetOutput(os.Stderr)
+	default:
 		return
 	}
 
-	logger.SetOutput(f)
 	logger.Info("GIF Maker started")
 }
 
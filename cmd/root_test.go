@@ -0,0 +1,56 @@
+// cmd/root_test.go
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/fatih/color"
+)
+
+func TestSetupColor(t *testing.T) {
+	savedNoColor, savedDisable, savedFlag := color.NoColor, core.DisableColor, noColor
+	t.Cleanup(func() {
+		color.NoColor, core.DisableColor, noColor = savedNoColor, savedDisable, savedFlag
+	})
+
+	tests := []struct {
+		name       string
+		env        bool
+		flag       bool
+		wantEscape bool
+	}{
+		{"NO_COLOR", true, false, false},
+		{"--no-color", false, true, false},
+		{"both", true, true, false},
+		{"neither", false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env {
+				t.Setenv("NO_COLOR", "1")
+			} else {
+				// t.Setenv restores the variable when the test ends
+				t.Setenv("NO_COLOR", "")
+				os.Unsetenv("NO_COLOR")
+			}
+			noColor = tt.flag
+			// Tests don't run on a terminal, so start from colors forced on
+			color.NoColor, core.DisableColor = false, false
+
+			setupColor()
+
+			var buf bytes.Buffer
+			color.New(color.FgYellow).Fprintf(&buf, "⚠️ %s", "warning")
+			if got := strings.Contains(buf.String(), "\x1b["); got != tt.wantEscape {
+				t.Errorf("output %q has ANSI escapes = %v, want %v", buf.String(), got, tt.wantEscape)
+			}
+			if core.DisableColor == tt.wantEscape {
+				t.Errorf("survey colors disabled = %v, want %v", core.DisableColor, !tt.wantEscape)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// cmd/rotation.go
+package cmd
+
+import (
+	"math"
+	"strconv"
+)
+
+// probedRotation returns how many degrees clockwise the probed video has to
+// be turned to display upright, rounded to a quarter turn. Phones record
+// this in a display matrix (reported as a counter-clockwise rotation), and
+// older files use a clockwise rotate tag.
+func probedRotation(info map[string]string) int {
+	if value, ok := info["rotation"]; ok {
+		if degrees, err := strconv.ParseFloat(value, 64); err == nil {
+			return normalizeRotation(-degrees)
+		}
+	}
+	if value, ok := info["TAG:rotate"]; ok {
+		if degrees, err := strconv.ParseFloat(value, 64); err == nil {
+			return normalizeRotation(degrees)
+		}
+	}
+	return 0
+}
+
+// normalizeRotation rounds degrees to the nearest quarter turn in [0, 360)
+func normalizeRotation(degrees float64) int {
+	quarters := int(math.Round(degrees / 90))
+	return ((quarters%4 + 4) % 4) * 90
+}
+
+// effectiveRotation combines the source's rotation metadata with --rotate
+func effectiveRotation() int {
+	return (opts.autoRotate + opts.Rotate) % 360
+}
+
+// skipAutorotate reports whether FFmpeg's own handling of rotation metadata
+// should be turned off: when the user asked to ignore the metadata, or when
+// it was probed and is applied explicitly. If probing failed, FFmpeg is left
+// to rotate on its own.
+func skipAutorotate() bool {
+	return opts.NoAutorotate || opts.rotateProbed
+}
@@ -0,0 +1,113 @@
+// cmd/scrubber.go
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// probeDuration returns the length of the input in seconds, or 0 if it
+// can't be determined
+func probeDuration(input string) float64 {
+	if video, err := ProbeVideo(input); err == nil && video.Duration > 0 {
+		return video.Duration
+	}
+
+	// Some containers only report a duration for the whole file
+	ffmpegPath, err := ffmpegManager.GetPath()
+	if err != nil {
+		return 0
+	}
+	duration, _, err := getVideoMetadata(input, ffmpegPath)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// parseClipPoint parses a position in the video given either as a
+// percentage of the total length (e.g. 25%) or as a timestamp
+func parseClipPoint(value string, total float64) (float64, error) {
+	value = strings.TrimSpace(value)
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || p < 0 || p > 100 {
+			return 0, fmt.Errorf("invalid percentage %q (expected 0%% to 100%%)", value)
+		}
+		return total * p / 100, nil
+	}
+
+	seconds, err := gifmaker.TimeToSeconds(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w (expected a percentage, HH:MM:SS, MM:SS or seconds)", err)
+	}
+	if seconds > total {
+		return 0, fmt.Errorf("%s is past the end of the video (%s)", value, formatTimestamp(total))
+	}
+	return seconds, nil
+}
+
+// promptClipRange asks for the start and end of the clip as percentages or
+// timestamps within a video of the given length, and sets opts.Start and
+// opts.End from the answers
+func promptClipRange(total float64) error {
+	fmt.Printf("Video length: %s (%.1f seconds)\n", formatTimestamp(total), total)
+
+	var startStr string
+	startQuestion := &survey.Input{
+		Message: "Start (percentage like 25% or time like 00:01:30):",
+		Default: "0%",
+	}
+	validateStart := func(ans interface{}) error {
+		start, err := parseClipPoint(ans.(string), total)
+		if err != nil {
+			return err
+		}
+		if start >= total {
+			return fmt.Errorf("start must be before the end of the video (%s)", formatTimestamp(total))
+		}
+		return nil
+	}
+	if err := survey.AskOne(startQuestion, &startStr, survey.WithValidator(validateStart)); err != nil {
+		return err
+	}
+	start, _ := parseClipPoint(startStr, total)
+
+	var endStr string
+	endQuestion := &survey.Input{
+		Message: "End (percentage like 75% or time like 00:02:00):",
+		Default: "100%",
+	}
+	validateEnd := func(ans interface{}) error {
+		end, err := parseClipPoint(ans.(string), total)
+		if err != nil {
+			return err
+		}
+		if end <= start {
+			return fmt.Errorf("end must be after the start (%s)", formatTimestamp(start))
+		}
+		return nil
+	}
+	if err := survey.AskOne(endQuestion, &endStr, survey.WithValidator(validateEnd)); err != nil {
+		return err
+	}
+	end, _ := parseClipPoint(endStr, total)
+
+	// Leave the bounds unset at the very start and end of the video
+	opts.Start = ""
+	if start > 0 {
+		opts.Start = formatTimestamp(start)
+	}
+	opts.End = ""
+	if end < total {
+		opts.End = formatTimestamp(end)
+	}
+
+	fmt.Printf("Clip: %s → %s (%.1f seconds)\n", formatTimestamp(start), formatTimestamp(end), end-start)
+	return nil
+}
@@ -0,0 +1,143 @@
+// cmd/segment.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// segment is one fixed-length piece of a --segment conversion
+type segment struct {
+	Start    float64
+	Duration float64
+	Output   string
+}
+
+// segmentOutputPath numbers an output filename, e.g. out.gif becomes
+// out-000.gif for the first segment
+func segmentOutputPath(output string, index int) string {
+	ext := filepath.Ext(output)
+	return fmt.Sprintf("%s-%03d%s", strings.TrimSuffix(output, ext), index, ext)
+}
+
+// splitIntoSegments cuts the clip into consecutive pieces of length seconds.
+// The last piece is shorter if the clip doesn't divide evenly.
+func splitIntoSegments(start, duration, length float64, output string) []segment {
+	if duration <= 0 || length <= 0 {
+		return nil
+	}
+
+	n := int(math.Ceil(duration/length - 1e-9))
+	segments := make([]segment, n)
+	for i := range segments {
+		segStart := start + float64(i)*length
+		segments[i] = segment{
+			Start:    segStart,
+			Duration: math.Min(length, start+duration-segStart),
+			Output:   segmentOutputPath(output, i),
+		}
+	}
+	return segments
+}
+
+// convertSegments converts the clip into consecutive GIFs of opts.Segment
+// seconds each
+func convertSegments(ctx context.Context, ffmpegPath string, progress *ProgressData, totalDuration float64) (*ConversionResult, error) {
+	start, duration := resolveClipRange(totalDuration)
+	segments := splitIntoSegments(start, duration, opts.Segment, opts.Output)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("could not determine video duration for --segment: %s", opts.Input)
+	}
+
+	return runSegments(ctx, ffmpegPath, progress, segments)
+}
+
+// runSegments converts each segment into its own output file
+func runSegments(ctx context.Context, ffmpegPath string, progress *ProgressData, segments []segment) (*ConversionResult, error) {
+	logger := GetLogger()
+
+	if opts.DryRun {
+		fmt.Println()
+		color.New(color.FgHiYellow, color.Bold).Printf("Dry run: %d outputs\n", len(segments))
+		for _, seg := range segments {
+			fmt.Printf("  %s  %s +%.2fs\n", seg.Output, formatTimestamp(seg.Start), seg.Duration)
+		}
+		fmt.Println()
+		color.Green("Dry run, nothing written.")
+		return nil, nil
+	}
+
+	// Each segment is converted with its own range and output
+	savedStart, savedDuration, savedEnd, savedOutput := opts.Start, opts.Duration, opts.End, opts.Output
+	defer func() {
+		opts.Start, opts.Duration, opts.End, opts.Output = savedStart, savedDuration, savedEnd, savedOutput
+	}()
+	opts.End = ""
+
+	startTime := time.Now()
+	frames := 0
+	for i, seg := range segments {
+		opts.Start = formatTimestamp(seg.Start)
+		opts.Duration = formatTimestamp(seg.Duration)
+		opts.Output = seg.Output
+
+		segProgress := &ProgressData{
+			StartTime:      time.Now(),
+			ProcessingRate: 1.0,
+			TotalDuration:  seg.Duration,
+			Width:          progress.Width,
+			Height:         progress.Height,
+		}
+		if opts.FPSMode == gifmaker.FPSModeCFR {
+			segProgress.TotalFrames = expectedFrameCount(seg.Duration, opts.FPS)
+		}
+
+		fmt.Printf("Segment %d/%d: %s\n", i+1, len(segments), seg.Output)
+		logger.Infof("Converting segment %d/%d (%s +%.2fs) to %s", i+1, len(segments), opts.Start, seg.Duration, seg.Output)
+		if err := runConversion(ctx, ffmpegPath, opts.HWAccel, segProgress); err != nil {
+			return nil, fmt.Errorf("segment %d failed: %w", i+1, err)
+		}
+		frames += segProgress.Frames
+	}
+
+	result := &ConversionResult{
+		Width:   progress.Width,
+		Height:  progress.Height,
+		Frames:  frames,
+		Elapsed: time.Since(startTime),
+	}
+	for i, seg := range segments {
+		output, err := statOutput(seg.Output, fmt.Sprintf("#%d (%s)", i+1, formatTime(seg.Start)))
+		if err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	return result, nil
+}
+
+// printSegmentSummary prints the summary box listing every segment GIF
+func printSegmentSummary(result *ConversionResult) {
+	logger := GetLogger()
+
+	fmt.Println()
+	color.New(color.FgHiGreen, color.Bold).Printf("✅ %d %ss created successfully!\n", len(result.Outputs), outputKind())
+
+	fmt.Println()
+	fmt.Println("┌─" + strings.Repeat("─", 50) + "┐")
+	for _, output := range result.Outputs {
+		fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprintf(" %s:", output.Label), fmt.Sprintf("%s (%.2f MB)", output.Path, output.SizeMB()))
+		logger.Infof("Segment written: %s (%.2f MB)", output.Path, output.SizeMB())
+	}
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Frames:"), fmt.Sprintf("%d frames at %s", result.Frames, formatOutputFPS()))
+	fmt.Printf("│ %-20s %-28s │\n", color.New(color.FgHiCyan).Sprint(" Conversion time:"), fmt.Sprintf("%.1f seconds", result.Elapsed.Seconds()))
+	fmt.Println("└─" + strings.Repeat("─", 50) + "┘")
+}
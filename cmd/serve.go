@@ -0,0 +1,334 @@
+// cmd/serve.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+type ServeOptions struct {
+	Addr          string
+	MaxConcurrent int           // Conversions running at once; further requests wait
+	Timeout       time.Duration // Per request, including the wait for a free slot
+	MaxUploadMB   int64
+}
+
+var serveOpts ServeOptions
+
+// Name of the multipart form field that carries the video
+const serveFormField = "video"
+
+// Multipart uploads bigger than this are spooled to disk instead of memory
+const serveFormMemory = 32 << 20
+
+// Protocols ffprobe and FFmpeg may open while reading an upload. An upload
+// can be a playlist (HLS, concat) naming URLs for the server to fetch.
+const serveProtocolWhitelist = "file,pipe"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP server that converts uploaded videos to GIFs",
+	Long: `Start a local HTTP server with a single endpoint:
+
+  POST /convert
+
+The video is sent as a multipart upload in the "video" field, and the
+options as query parameters: fps, width, quality, start and duration, with
+the same meaning and defaults as for convert. The response is the GIF, or a
+plain-text error with a 4xx or 5xx status.
+
+  curl -F video=@clip.mp4 "http://localhost:8080/convert?fps=15&width=480" -o clip.gif
+
+Uploads are written to a temporary directory that is removed when the
+request finishes, whether or not the conversion succeeded.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := GetLogger()
+
+		if serveOpts.MaxConcurrent < 1 {
+			return usageErrorf("--max-concurrent must be at least 1 (got %d)", serveOpts.MaxConcurrent)
+		}
+		if serveOpts.Timeout <= 0 {
+			return usageErrorf("--timeout must be greater than 0 (got %s)", serveOpts.Timeout)
+		}
+		if serveOpts.MaxUploadMB < 1 {
+			return usageErrorf("--max-upload-size must be at least 1 MB (got %d)", serveOpts.MaxUploadMB)
+		}
+
+		// Extract the embedded FFmpeg once rather than per request
+		if err := checkFFmpegInstallation(); err != nil {
+			return err
+		}
+		ffmpegPath, err := ffmpegManager.GetPath()
+		if err != nil {
+			return &ffmpegUnavailableError{fmt.Errorf("Failed to get FFmpeg: %w", err)}
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/convert", newConvertHandler(ffmpegPath, serveOpts))
+		// The request timeout covers the upload too; writing gets some
+		// extra time so a timed out request can still be answered
+		server := &http.Server{
+			Addr:              serveOpts.Addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       serveOpts.Timeout,
+			WriteTimeout:      serveOpts.Timeout + 30*time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		serveErr := make(chan error, 1)
+		go func() { serveErr <- server.ListenAndServe() }()
+
+		color.Green("Listening on http://%s/convert (up to %d conversions at once)", serveOpts.Addr, serveOpts.MaxConcurrent)
+		logger.Infof("Serving on %s, max %d concurrent conversions, %s timeout", serveOpts.Addr, serveOpts.MaxConcurrent, serveOpts.Timeout)
+
+		select {
+		case err := <-serveErr:
+			return fmt.Errorf("server failed: %w", err)
+		case <-ctx.Done():
+		}
+
+		// Let running conversions finish, within reason
+		fmt.Println("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveOpts.Timeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("server shutdown: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveOpts.Addr, "addr", "127.0.0.1:8080", "Address to listen on (use :8080 to accept connections from other machines)")
+	serveCmd.Flags().IntVar(&serveOpts.MaxConcurrent, "max-concurrent", 2, "Maximum conversions to run at once; further requests wait for a free slot")
+	serveCmd.Flags().DurationVar(&serveOpts.Timeout, "timeout", 5*time.Minute, "Maximum time for a request, including upload, waiting and conversion")
+	serveCmd.Flags().Int64Var(&serveOpts.MaxUploadMB, "max-upload-size", 500, "Largest accepted upload in megabytes")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+// convertHandler serves POST /convert
+type convertHandler struct {
+	ffmpegPath string
+	opts       ServeOptions
+	slots      chan struct{} // Holds a token for every running conversion
+}
+
+func newConvertHandler(ffmpegPath string, opts ServeOptions) *convertHandler {
+	return &convertHandler{
+		ffmpegPath: ffmpegPath,
+		opts:       opts,
+		slots:      make(chan struct{}, opts.MaxConcurrent),
+	}
+}
+
+// httpError is an error with the status to answer it with
+type httpError struct {
+	Status int
+	Err    error
+}
+
+func (e *httpError) Error() string { return e.Err.Error() }
+func (e *httpError) Unwrap() error { return e.Err }
+
+// httpErrorf formats an error that is answered with the given status
+func httpErrorf(status int, format string, args ...any) error {
+	return &httpError{Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+func (h *convertHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := GetLogger()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST with a multipart video upload", http.StatusMethodNotAllowed)
+		return
+	}
+
+	start := time.Now()
+	err := h.convert(w, r)
+	if err == nil {
+		logger.Infof("%s %s from %s converted in %.1fs", r.Method, r.URL, r.RemoteAddr, time.Since(start).Seconds())
+		return
+	}
+
+	status := http.StatusInternalServerError
+	var he *httpError
+	var ffmpegErr *gifmaker.FFmpegError
+	switch {
+	case errors.As(err, &he):
+		status = he.Status
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+		err = fmt.Errorf("conversion did not finish within %s", h.opts.Timeout)
+	case errors.As(err, &ffmpegErr):
+		status = http.StatusUnprocessableEntity
+	}
+	logger.Warnf("%s %s from %s failed (%d): %v", r.Method, r.URL, r.RemoteAddr, status, err)
+	http.Error(w, err.Error(), status)
+}
+
+// convert runs one request: it stores the upload, converts it and writes
+// the GIF to w. Nothing is written to w if it returns an error.
+func (h *convertHandler) convert(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), h.opts.Timeout)
+	defer cancel()
+
+	convOpts, err := parseServeQuery(r.URL.Query())
+	if err != nil {
+		return &httpError{Status: http.StatusBadRequest, Err: err}
+	}
+
+	tempDir, err := os.MkdirTemp(tempRoot, "gif-maker-serve")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTemp(tempDir)
+
+	input, name, err := h.saveUpload(w, r, tempDir)
+	if err != nil {
+		return err
+	}
+	if err := checkVideoInput(input, "-protocol_whitelist", serveProtocolWhitelist); err != nil {
+		// Name the upload rather than where it was stored
+		return httpErrorf(http.StatusUnsupportedMediaType, "%s", strings.ReplaceAll(err.Error(), input, name))
+	}
+
+	// Wait for a free slot, but not past the deadline
+	select {
+	case h.slots <- struct{}{}:
+		defer func() { <-h.slots }()
+	case <-ctx.Done():
+		return httpErrorf(http.StatusServiceUnavailable, "server busy: no conversion slot became free within %s", h.opts.Timeout)
+	}
+
+	convOpts.FFmpegPath = h.ffmpegPath
+	convOpts.Input = input
+	convOpts.ProtocolWhitelist = serveProtocolWhitelist
+	convOpts.Output = filepath.Join(tempDir, "output.gif")
+	convOpts.LogLevel = "error"
+	result, err := gifmaker.Convert(ctx, convOpts, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	gif, err := os.Open(convOpts.Output)
+	if err != nil {
+		return fmt.Errorf("failed to open the GIF: %w", err)
+	}
+	defer gif.Close()
+	stat, err := gif.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat the GIF: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Content-Length", strconv.FormatInt(stat.Size(), 10))
+	w.Header().Set("X-Gif-Maker-Frames", strconv.Itoa(result.Frames))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, gif); err != nil {
+		// The status is already sent, so all that's left is to log it
+		GetLogger().Warnf("Failed to send the GIF to %s: %v", r.RemoteAddr, err)
+	}
+	return nil
+}
+
+// saveUpload stores the uploaded video in dir and returns its path and the
+// file name the client gave it
+func (h *convertHandler) saveUpload(w http.ResponseWriter, r *http.Request, dir string) (string, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxUploadMB<<20)
+	if err := r.ParseMultipartForm(serveFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return "", "", httpErrorf(http.StatusRequestEntityTooLarge, "upload is larger than %d MB", h.opts.MaxUploadMB)
+		}
+		return "", "", httpErrorf(http.StatusBadRequest, "expected a multipart/form-data upload: %v", err)
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(serveFormField)
+	if err != nil {
+		return "", "", httpErrorf(http.StatusBadRequest, "missing %q file field in the upload", serveFormField)
+	}
+	defer file.Close()
+
+	// Keep the extension as a hint for FFmpeg, but never the client's path
+	path := filepath.Join(dir, "input"+filepath.Ext(filepath.Base(header.Filename)))
+	out, err := os.Create(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to store the upload: %w", err)
+	}
+	if _, err := io.Copy(out, file); err != nil {
+		out.Close()
+		return "", "", fmt.Errorf("failed to store the upload: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to store the upload: %w", err)
+	}
+	return path, header.Filename, nil
+}
+
+// parseServeQuery reads the conversion options from the query parameters,
+// defaulting them like convert does
+func parseServeQuery(query url.Values) (gifmaker.Options, error) {
+	convOpts := gifmaker.Options{
+		FPS:     10,
+		Quality: 90,
+		Start:   query.Get("start"),
+	}
+
+	ints := []struct {
+		name     string
+		dst      *int
+		min, max int
+	}{
+		{"fps", &convOpts.FPS, 1, 50},
+		{"width", &convOpts.Width, 1, 10000},
+		{"quality", &convOpts.Quality, 1, 100},
+	}
+	for _, p := range ints {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < p.min || n > p.max {
+			return convOpts, fmt.Errorf("invalid %s %q (expected %d-%d)", p.name, value, p.min, p.max)
+		}
+		*p.dst = n
+	}
+
+	convOpts.Duration = query.Get("duration")
+	for _, t := range []struct{ name, value string }{
+		{"start", convOpts.Start},
+		{"duration", convOpts.Duration},
+	} {
+		if _, err := gifmaker.TimeToSeconds(t.value); err != nil {
+			return convOpts, fmt.Errorf("invalid %s: %w (expected HH:MM:SS, MM:SS or seconds)", t.name, err)
+		}
+	}
+	return convOpts, nil
+}
@@ -0,0 +1,105 @@
+// cmd/serve_test.go
+package cmd
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// uploadRequest builds a POST /convert request carrying content as the
+// uploaded video
+func uploadRequest(t *testing.T, query string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(serveFormField, "clip.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/convert?"+query, &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return r
+}
+
+func TestConvertHandlerErrors(t *testing.T) {
+	h := newConvertHandler("ffmpeg", ServeOptions{MaxConcurrent: 1, Timeout: time.Minute, MaxUploadMB: 1})
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		status  int
+	}{
+		{"GET", httptest.NewRequest(http.MethodGet, "/convert", nil), http.StatusMethodNotAllowed},
+		{"bad fps", uploadRequest(t, "fps=fast", []byte("video")), http.StatusBadRequest},
+		{"fps out of range", uploadRequest(t, "fps=0", []byte("video")), http.StatusBadRequest},
+		{"oversized upload", uploadRequest(t, "fps=10", make([]byte, 2<<20)), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.request)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d (body %q)", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+// Stand-in for ffprobe and FFmpeg that records its arguments. As FFmpeg it
+// writes a GIF header to the output.
+const recordingTool = `#!/bin/sh
+echo "$@" >> "$0.args"
+case "$0" in
+*ffprobe) echo codec_name=h264 ;;
+*) for last; do :; done; printf 'GIF89a' > "$last" ;;
+esac
+`
+
+func TestConvertHandlerProtocolWhitelist(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs shell scripts standing in for ffprobe and FFmpeg")
+	}
+
+	bin := t.TempDir()
+	for _, name := range []string{"ffprobe", "ffmpeg"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(recordingTool), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+
+	h := newConvertHandler(filepath.Join(bin, "ffmpeg"), ServeOptions{MaxConcurrent: 1, Timeout: time.Minute, MaxUploadMB: 1})
+	mp4 := append([]byte{0, 0, 0, 0x18}, "ftypisom"...)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, uploadRequest(t, "fps=10", mp4))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %q)", w.Code, w.Body.String())
+	}
+
+	// Both tools have to get the whitelist before the upload
+	for _, name := range []string{"ffprobe", "ffmpeg"} {
+		data, err := os.ReadFile(filepath.Join(bin, name+".args"))
+		if err != nil {
+			t.Fatalf("%s was not run: %v", name, err)
+		}
+		args := string(data)
+		whitelist := strings.Index(args, "-protocol_whitelist "+serveProtocolWhitelist)
+		input := strings.Index(args, "input.mp4")
+		if whitelist < 0 || whitelist > input {
+			t.Errorf("%s args %q, want -protocol_whitelist %s before the input", name, args, serveProtocolWhitelist)
+		}
+	}
+}
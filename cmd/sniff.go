@@ -0,0 +1,111 @@
+// cmd/sniff.go
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/fatih/color"
+)
+
+// sniffSize is how much of a file is read to recognize its container
+const sniffSize = 512
+
+// containerSignature is a byte pattern that marks a container format
+type containerSignature struct {
+	Format string
+	Offset int
+	Magic  []byte
+}
+
+// Container signatures FFmpeg can read. Formats that share a signature
+// (mp4 and mov, mkv and webm) are told apart by ffprobe, not here.
+var containerSignatures = []containerSignature{
+	{"mp4", 4, []byte("ftyp")},
+	{"mov", 4, []byte("moov")},
+	{"mov", 4, []byte("mdat")},
+	{"mov", 4, []byte("wide")},
+	{"mkv", 0, []byte{0x1a, 0x45, 0xdf, 0xa3}},
+	{"avi", 8, []byte("AVI ")},
+	{"flv", 0, []byte("FLV")},
+	{"mpeg", 0, []byte{0x00, 0x00, 0x01, 0xba}},
+	{"asf", 0, []byte{0x30, 0x26, 0xb2, 0x75, 0x8e, 0x66, 0xcf, 0x11}},
+	{"ogg", 0, []byte("OggS")},
+	{"gif", 0, []byte("GIF8")},
+}
+
+// sniffContainer reads the start of a file and returns the container it
+// looks like, or "" if it isn't recognized
+func sniffContainer(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return matchContainer(head[:n]), nil
+}
+
+// matchContainer returns the container whose signature head starts with
+func matchContainer(head []byte) string {
+	for _, sig := range containerSignatures {
+		end := sig.Offset + len(sig.Magic)
+		if len(head) >= end && bytes.Equal(head[sig.Offset:end], sig.Magic) {
+			return sig.Format
+		}
+	}
+
+	// MPEG transport streams have a sync byte every 188 bytes
+	if len(head) > 2*188 && head[0] == 0x47 && head[188] == 0x47 && head[2*188] == 0x47 {
+		return "mpegts"
+	}
+	return ""
+}
+
+// checkVideoInput makes sure a file is a video FFmpeg can decode, whatever
+// its extension. It only rejects a file ffprobe found no video stream in,
+// or one that neither looks like a known container nor has a video
+// extension. If ffprobe is missing or fails, FFmpeg gets to try anyway.
+// ffprobeArgs are passed to ffprobe right before the file.
+func checkVideoInput(path string, ffprobeArgs ...string) error {
+	logger := GetLogger()
+
+	container, err := sniffContainer(path)
+	if err != nil {
+		return err
+	}
+
+	video, err := ProbeVideo(path, ffprobeArgs...)
+	if err != nil {
+		if container == "" && !isValidVideoFile(path) {
+			return usageErrorf("%s is not a recognized video (supported containers include mp4, mov, mkv, webm, avi and gif)", path)
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			logger.Debugf("ffprobe is not available, recognizing %s by its content", path)
+			return nil
+		}
+		warning := fmt.Sprintf("ffprobe could not read %s (%v); trying FFmpeg anyway", path, err)
+		color.Yellow("⚠️ %s", warning)
+		logger.Warn(warning)
+		return nil
+	}
+	if !video.HasVideo() {
+		return usageErrorf("%s has no video stream (GIFs are video only; audio-only files can't be converted)", path)
+	}
+
+	if container == "" {
+		logger.Debugf("%s has no known container signature, but ffprobe found %s video", path, video.Codec)
+	} else {
+		logger.Debugf("%s looks like %s and ffprobe found %s video", path, container, video.Codec)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// cmd/sniff_test.go
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Stand-ins for ffprobe
+var fakeFFprobes = map[string]string{
+	"video":  "#!/bin/sh\necho codec_name=h264\necho width=640\necho height=360\n",
+	"audio":  "#!/bin/sh\nexit 0\n",
+	"broken": "#!/bin/sh\necho 'Invalid data found when processing input' >&2\nexit 1\n",
+}
+
+func TestCheckVideoInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for ffprobe")
+	}
+
+	mp4 := append([]byte{0, 0, 0, 0x18}, "ftypisom"...)
+	unknown := []byte("just some bytes")
+
+	tests := []struct {
+		name    string
+		ffprobe string // Empty when ffprobe isn't installed
+		file    string
+		content []byte
+		wantErr bool
+	}{
+		{"video", "video", "clip.mp4", mp4, false},
+		{"no video stream", "audio", "song.mp4", mp4, true},
+		{"probe fails on a known container", "broken", "clip.bin", mp4, false},
+		{"probe fails on a video extension", "broken", "clip.mp4", unknown, false},
+		{"probe fails on anything else", "broken", "notes.txt", unknown, true},
+		{"no ffprobe, known container", "", "clip.bin", mp4, false},
+		{"no ffprobe, video extension", "", "clip.mkv", unknown, false},
+		{"no ffprobe, anything else", "", "notes.txt", unknown, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := t.TempDir()
+			if tt.ffprobe != "" {
+				if err := os.WriteFile(filepath.Join(bin, "ffprobe"), []byte(fakeFFprobes[tt.ffprobe]), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", bin)
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := checkVideoInput(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkVideoInput(%s) = %v, want error %v", tt.file, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// cmd/statsmode.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Frames sampled across the clip to pick a palette stats mode, and the
+// tiny size they are scaled to. Only the colors matter, not the detail.
+const (
+	statsSampleFrames = 60
+	statsSampleWidth  = 32
+	statsSampleHeight = 18
+)
+
+// Average share of the colors that change between sampled frames below
+// which a clip counts as static, and above which it counts as a sequence
+// of different scenes
+const (
+	staticColorChange = 0.05
+	sceneColorChange  = 0.25
+)
+
+// Color histogram bins per channel
+const histogramBins = 4
+
+// sampleColorFrames decodes evenly spaced, tiny RGB frames from the clip
+func sampleColorFrames(ffmpegPath string, start, duration float64) ([][]byte, error) {
+	logger := GetLogger()
+
+	rate := 2.0
+	if duration > 0 {
+		rate = float64(statsSampleFrames) / duration
+	}
+	if rate > 5 {
+		rate = 5
+	}
+
+	args := []string{"-loglevel", "error"}
+	if start > 0 {
+		args = append(args, "-ss", formatSeconds(start))
+	}
+	args = append(args, "-i", opts.Input)
+	if duration > 0 {
+		args = append(args, "-t", formatSeconds(duration))
+	}
+	args = append(args,
+		"-vf", fmt.Sprintf("fps=%s,scale=%d:%d", strconv.FormatFloat(rate, 'f', 4, 64), statsSampleWidth, statsSampleHeight),
+		"-frames:v", strconv.Itoa(statsSampleFrames),
+		"-f", "rawvideo",
+		"-pix_fmt", "rgb24",
+		"-",
+	)
+
+	logger.Debugf("FFmpeg stats sampling command: %s %s", ffmpegPath, strings.Join(args, " "))
+	output, err := exec.Command(ffmpegPath, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample frames: %w", err)
+	}
+
+	frameSize := statsSampleWidth * statsSampleHeight * 3
+	var frames [][]byte
+	for len(output) >= frameSize {
+		frames = append(frames, output[:frameSize])
+		output = output[frameSize:]
+	}
+	return frames, nil
+}
+
+// colorHistogram returns the share of an RGB frame's pixels in each coarse
+// color bin
+func colorHistogram(frame []byte) []float64 {
+	hist := make([]float64, histogramBins*histogramBins*histogramBins)
+	pixels := len(frame) / 3
+	if pixels == 0 {
+		return hist
+	}
+	for i := 0; i+2 < len(frame); i += 3 {
+		r := int(frame[i]) * histogramBins / 256
+		g := int(frame[i+1]) * histogramBins / 256
+		b := int(frame[i+2]) * histogramBins / 256
+		hist[(r*histogramBins+g)*histogramBins+b]++
+	}
+	for i := range hist {
+		hist[i] /= float64(pixels)
+	}
+	return hist
+}
+
+// histogramChange returns the share of colors that differ between two
+// histograms, from 0 (same colors) to 1 (no colors in common)
+func histogramChange(a, b []float64) float64 {
+	var diff float64
+	for i := range a {
+		if a[i] > b[i] {
+			diff += a[i] - b[i]
+		} else {
+			diff += b[i] - a[i]
+		}
+	}
+	return diff / 2
+}
+
+// chooseStatsMode picks a palettegen stats mode from the average color
+// change between consecutive sampled frames. Static clips are mostly one
+// background, so the palette is best spent on what moves (diff); clips
+// whose colors change completely get a palette per frame (single).
+func chooseStatsMode(frames [][]byte) (string, float64) {
+	if len(frames) < 2 {
+		return gifmaker.StatsModeDiff, 0
+	}
+
+	var total float64
+	prev := colorHistogram(frames[0])
+	for _, frame := range frames[1:] {
+		hist := colorHistogram(frame)
+		total += histogramChange(prev, hist)
+		prev = hist
+	}
+	change := total / float64(len(frames)-1)
+
+	switch {
+	case change < staticColorChange:
+		return gifmaker.StatsModeDiff, change
+	case change > sceneColorChange:
+		return gifmaker.StatsModeSingle, change
+	default:
+		return gifmaker.StatsModeFull, change
+	}
+}
+
+// autoStatsMode samples the clip and sets opts.statsMode. If sampling
+// fails the default mode is kept.
+func autoStatsMode(ffmpegPath string, totalDuration float64) {
+	logger := GetLogger()
+
+	start, duration := resolveClipRange(totalDuration)
+	frames, err := sampleColorFrames(ffmpegPath, start, duration)
+	if err != nil {
+		logger.Warnf("Could not sample the clip for --auto-stats-mode, using %s: %v", gifmaker.StatsModeDiff, err)
+		return
+	}
+
+	mode, change := chooseStatsMode(frames)
+	opts.statsMode = mode
+	logger.Infof("Auto stats mode: colors change by %.1f%% between %d sampled frames, using %s", change*100, len(frames), mode)
+	fmt.Printf("Palette stats mode: %s (colors change %.1f%% between samples)\n", mode, change*100)
+}
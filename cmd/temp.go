@@ -0,0 +1,65 @@
+// cmd/temp.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Temporary files kept because of --keep-temp, printed before exiting
+var (
+	retainedMu sync.Mutex
+	retained   []string
+)
+
+// removeTemp removes a temporary file or directory. With --keep-temp it is
+// kept and its path recorded instead.
+func removeTemp(path string) {
+	if !keepTemp {
+		os.RemoveAll(path)
+		return
+	}
+
+	retainedMu.Lock()
+	defer retainedMu.Unlock()
+
+	for _, p := range retained {
+		if p == path {
+			return
+		}
+	}
+	retained = append(retained, path)
+	GetLogger().Infof("Keeping temporary path %s", path)
+}
+
+// cleanupFFmpeg removes the extracted FFmpeg binary, unless --keep-temp
+// is set
+func cleanupFFmpeg() {
+	if ffmpegManager == nil {
+		return
+	}
+	if keepTemp {
+		if path := ffmpegManager.ExtractedBinary(); path != "" {
+			removeTemp(path)
+		}
+		return
+	}
+	if err := ffmpegManager.Cleanup(); err != nil {
+		GetLogger().Warnf("Failed to remove extracted FFmpeg: %v", err)
+	}
+}
+
+// printRetainedTemp lists the temporary files --keep-temp kept
+func printRetainedTemp() {
+	retainedMu.Lock()
+	defer retainedMu.Unlock()
+
+	if len(retained) == 0 {
+		return
+	}
+	fmt.Println("Kept temporary files:")
+	for _, path := range retained {
+		fmt.Printf("  %s\n", path)
+	}
+}
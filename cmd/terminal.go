@@ -0,0 +1,90 @@
+// cmd/terminal.go
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// How often plain-text progress lines are printed
+const plainProgressInterval = 2 * time.Second
+
+// Weight of the newest processing rate sample in the smoothed rate. FFmpeg
+// reports speed ten times a second and it swings a lot between frames.
+const rateSmoothing = 0.1
+
+// stdoutIsTerminal reports whether stdout is an interactive terminal
+func stdoutIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Progress bar width when stdout isn't a terminal or its size is unknown
+const defaultProgressWidth = 80
+
+// progressBarWidth returns the width for progress bars: the width of the
+// terminal stdout is attached to. Stdin isn't asked, since it's often a
+// pipe even when the output goes to a wide terminal.
+func progressBarWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return defaultProgressWidth
+	}
+	return width
+}
+
+// showProgressBars reports whether animated progress bars should be drawn.
+// They rely on cursor movement escapes, which garble redirected output.
+func showProgressBars() bool {
+	return !opts.NoProgress && opts.ProgressFormat != progressFormatJSON && stdoutIsTerminal()
+}
+
+// formatPlainProgress formats a progress line without any escape codes
+func formatPlainProgress(current, total float64) string {
+	if total <= 0 {
+		return fmt.Sprintf("progress: %.1fs", current)
+	}
+	percent := math.Min(current/total, 1) * 100
+	return fmt.Sprintf("progress: %.0f%% (%.1fs/%.1fs)", percent, current, total)
+}
+
+// plainProgressReporter returns a progress callback that prints a plain
+// progress line every few seconds, for logs and CI output
+func plainProgressReporter(totalDuration float64) func(gifmaker.Progress) {
+	var last time.Time
+	return func(update gifmaker.Progress) {
+		if update.Done {
+			fmt.Println(formatPlainProgress(math.Max(update.CurrentTime, totalDuration), totalDuration))
+			return
+		}
+		if update.CurrentTime <= 0 || time.Since(last) < plainProgressInterval {
+			return
+		}
+		last = time.Now()
+		fmt.Println(formatPlainProgress(update.CurrentTime, totalDuration))
+	}
+}
+
+// smoothRate folds a new processing rate sample into an exponential moving
+// average. A zero average means there is no history yet.
+func smoothRate(average, sample float64) float64 {
+	if average <= 0 {
+		return sample
+	}
+	return average + rateSmoothing*(sample-average)
+}
+
+// formatRemaining estimates the time left from the clip position and the
+// smoothed processing rate
+func formatRemaining(current, total, rate float64) string {
+	if total <= 0 || rate <= 0 {
+		return "-- left"
+	}
+	remaining := math.Max(total-current, 0) / rate
+	return fmt.Sprintf("%s left", formatTime(math.Ceil(remaining)))
+}
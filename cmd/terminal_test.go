@@ -0,0 +1,49 @@
+// cmd/terminal_test.go
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmoothRate(t *testing.T) {
+	// The first sample is taken as is
+	if got := smoothRate(0, 2.5); got != 2.5 {
+		t.Errorf("smoothRate(0, 2.5) = %g, want 2.5", got)
+	}
+
+	// Each sample moves the average a tenth of the way towards it
+	if got := smoothRate(2, 3); math.Abs(got-2.1) > 1e-9 {
+		t.Errorf("smoothRate(2, 3) = %g, want 2.1", got)
+	}
+	if got := smoothRate(2, 1); math.Abs(got-1.9) > 1e-9 {
+		t.Errorf("smoothRate(2, 1) = %g, want 1.9", got)
+	}
+
+	// After a jump the gap shrinks by a factor 0.9 per sample, so it
+	// converges without overshooting
+	average := smoothRate(0, 1)
+	for i := 1; i <= 50; i++ {
+		average = smoothRate(average, 2)
+		want := 2 - math.Pow(0.9, float64(i))
+		if math.Abs(average-want) > 1e-9 {
+			t.Fatalf("after %d samples average = %g, want %g", i, average, want)
+		}
+	}
+	if math.Abs(average-2) > 0.01 {
+		t.Errorf("average = %g after 50 samples of 2, want within 0.01 of 2", average)
+	}
+
+	// Noisy samples around a rate average out to it
+	average = 0
+	for i := 0; i < 200; i++ {
+		sample := 1.5
+		if i%2 == 0 {
+			sample = 0.5
+		}
+		average = smoothRate(average, sample)
+	}
+	if math.Abs(average-1) > 0.06 {
+		t.Errorf("average of alternating 0.5 and 1.5 = %g, want about 1", average)
+	}
+}
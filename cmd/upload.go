@@ -0,0 +1,180 @@
+// cmd/upload.go
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// How long an upload may take before it's abandoned
+const uploadTimeout = 5 * time.Minute
+
+// Uploader publishes a finished GIF and returns a shareable URL
+type Uploader interface {
+	// Name is the provider name shown in messages
+	Name() string
+	Upload(ctx context.Context, path string) (string, error)
+}
+
+// uploaders maps --upload values to provider constructors. Constructors
+// check their configuration so a missing API key is reported before
+// converting rather than after.
+var uploaders = map[string]func() (Uploader, error){
+	"imgur": newImgurUploader,
+}
+
+// uploaderNames returns the supported --upload values
+func uploaderNames() []string {
+	names := make([]string, 0, len(uploaders))
+	for name := range uploaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newUploader returns the upload provider for a --upload value
+func newUploader(name string) (Uploader, error) {
+	newFunc, ok := uploaders[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown upload provider %q (valid: %s)", name, strings.Join(uploaderNames(), ", "))
+	}
+	return newFunc()
+}
+
+// uploadOutput uploads the finished GIF, prints its URL and copies the URL
+// to the clipboard if possible. The local file is kept either way.
+func uploadOutput(ctx context.Context, uploader Uploader, path string) error {
+	logger := GetLogger()
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	fmt.Printf("Uploading %s to %s...\n", path, uploader.Name())
+	url, err := uploader.Upload(ctx, path)
+	if err != nil {
+		return fmt.Errorf("upload to %s failed (the GIF is still saved at %s): %w", uploader.Name(), path, err)
+	}
+	logger.Infof("Uploaded %s to %s", path, url)
+
+	color.New(color.FgHiGreen, color.Bold).Printf("🔗 %s\n", url)
+	if err := copyToClipboard(url); err != nil {
+		logger.Debugf("Could not copy URL to clipboard: %v", err)
+	} else {
+		fmt.Println("URL copied to clipboard")
+	}
+
+	return nil
+}
+
+// imgurClientIDEnvVar holds the Imgur API client ID
+const imgurClientIDEnvVar = "IMGUR_CLIENT_ID"
+
+// imgurUploadURL is the Imgur anonymous upload endpoint
+const imgurUploadURL = "https://api.imgur.com/3/image"
+
+// imgurUploader uploads anonymously to Imgur with an API client ID
+type imgurUploader struct {
+	clientID string
+	client   *http.Client
+}
+
+// newImgurUploader reads the client ID from the environment
+func newImgurUploader() (Uploader, error) {
+	clientID := strings.TrimSpace(os.Getenv(imgurClientIDEnvVar))
+	if clientID == "" {
+		return nil, fmt.Errorf("uploading to Imgur needs an API client ID in %s (register an application at https://api.imgur.com/oauth2/addclient)", imgurClientIDEnvVar)
+	}
+	return &imgurUploader{clientID: clientID, client: http.DefaultClient}, nil
+}
+
+func (u *imgurUploader) Name() string {
+	return "Imgur"
+}
+
+// imgurResponse is the subset of Imgur's API response we use
+type imgurResponse struct {
+	Data struct {
+		Link  string `json:"link"`
+		Error any    `json:"error"`
+	} `json:"data"`
+	Success bool `json:"success"`
+	Status  int  `json:"status"`
+}
+
+func (u *imgurUploader) Upload(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", filepath.Base(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := form.WriteField("type", "file"); err != nil {
+		return "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, imgurUploadURL, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Client-ID "+u.clientID)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("Imgur rate limit reached, try again later%s", imgurRateLimitReset(resp.Header))
+	}
+
+	var result imgurResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("unexpected response from Imgur (%s): %w", resp.Status, err)
+	}
+	if !result.Success || result.Data.Link == "" {
+		if result.Data.Error != nil {
+			return "", fmt.Errorf("Imgur returned %s: %v", resp.Status, result.Data.Error)
+		}
+		return "", fmt.Errorf("Imgur returned %s", resp.Status)
+	}
+
+	return result.Data.Link, nil
+}
+
+// imgurRateLimitReset describes when Imgur's rate limit resets, or returns
+// "" if the response doesn't say
+func imgurRateLimitReset(header http.Header) string {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-UserReset"), 10, 64)
+	if err != nil || reset <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (resets at %s)", time.Unix(reset, 0).Format("15:04"))
+}
@@ -3,10 +3,13 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"math"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
 )
 
 // CheckFFmpeg checks if FFmpeg is installed and returns an error if not
@@ -18,27 +21,30 @@ func CheckFFmpeg() error {
 	return nil
 }
 
-// GetVideoInfo uses FFmpeg to extract basic information about a video file
-func GetVideoInfo(videoPath string) (map[string]string, error) {
-	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("video file does not exist: %s", videoPath)
-	}
-
-	// Run ffprobe to get video info
-	cmd := exec.Command("ffprobe",
+// runFFprobe uses ffprobe to extract the raw properties of the first video
+// stream of a file. The result is empty if the file has no video stream.
+// inputArgs go right before the file, e.g. -protocol_whitelist.
+func runFFprobe(videoPath string, inputArgs ...string) (map[string]string, error) {
+	args := []string{
 		"-v", "error",
 		"-select_streams", "v:0",
-		"-show_entries", "stream=width,height,duration,r_frame_rate",
+		"-show_entries", "stream=width,height,duration,r_frame_rate,pix_fmt,codec_name,color_range,color_transfer,color_primaries:stream_tags=alpha_mode,rotate:stream_side_data=rotation",
 		"-of", "default=noprint_wrappers=1",
-		videoPath)
+	}
+	args = append(args, inputArgs...)
+	cmd := exec.Command("ffprobe", append(args, videoPath)...)
 
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get video info: %w", err)
 	}
 
-	// Parse the output
-	lines := strings.Split(string(output), "\n")
+	return parseFFprobeOutput(string(output)), nil
+}
+
+// parseFFprobeOutput parses ffprobe's key=value output into a map
+func parseFFprobeOutput(output string) map[string]string {
+	lines := strings.Split(output, "\n")
 	info := make(map[string]string)
 
 	for _, line := range lines {
@@ -51,7 +57,7 @@ func GetVideoInfo(videoPath string) (map[string]string, error) {
 		}
 	}
 
-	return info, nil
+	return info
 }
 
 // GetOptimalThreads returns the optimal number of threads to use based on CPU cores
@@ -80,40 +86,101 @@ func HumanizeBytes(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// ValidateTimeFormat checks if a time string is in the format HH:MM:SS or HH:MM:SS.MS
+// Default number of palette colors used for conversions
+const defaultPaletteColors = 256
+
+// EstimateGIFSize estimates a GIF's size in bytes. Each pixel is stored as a
+// palette index of log2(colors) bits and LZW-compressed; dithering adds
+// noise that compresses worse. After the first frame only the changed
+// rectangle is stored, so later frames cost a fraction of a full one.
+func EstimateGIFSize(width, height, frames, colors int, dithered bool) int64 {
+	if width <= 0 || height <= 0 || frames <= 0 {
+		return 0
+	}
+	if colors < 2 {
+		colors = 2
+	} else if colors > 256 {
+		colors = 256
+	}
+
+	// Bits per palette index, as stored before compression
+	bits := math.Ceil(math.Log2(float64(colors)))
+	frameBytes := float64(width*height) * bits / 8
+
+	// Typical LZW ratios for video content
+	compression := 0.45
+	if dithered {
+		compression = 0.65
+	}
+
+	// Share of each later frame that differs from the previous one
+	const changedFraction = 0.6
+
+	// Header, logical screen descriptor and global color table
+	overhead := 800 + 3*colors
+
+	size := frameBytes * compression * (1 + changedFraction*float64(frames-1))
+	return int64(size) + int64(overhead)
+}
+
+// ValidateTimeFormat checks if a time string is in the format HH:MM:SS,
+// MM:SS or SS, optionally with fractional seconds
 func ValidateTimeFormat(timeStr string) bool {
-	if timeStr == "" {
-		return true
-	}
-
-	parts := strings.Split(timeStr, ":")
-	if len(parts) != 3 {
-		return false
-	}
-
-	// Check if each part is a valid number
-	for i, part := range parts {
-		// For seconds, we might have decimal points
-		if i == 2 && strings.Contains(part, ".") {
-			secParts := strings.Split(part, ".")
-			if len(secParts) != 2 {
-				return false
-			}
-
-			// Check if both parts of seconds are valid numbers
-			if _, err := fmt.Sscanf(secParts[0], "%d", new(int)); err != nil {
-				return false
-			}
-
-			if _, err := fmt.Sscanf(secParts[1], "%d", new(int)); err != nil {
-				return false
-			}
-		} else {
-			if _, err := fmt.Sscanf(part, "%d", new(int)); err != nil {
-				return false
-			}
+	_, err := gifmaker.TimeToSeconds(timeStr)
+	return err == nil
+}
+
+// parseFrameRate parses an FFmpeg frame rate, which is either a plain number
+// or a fraction such as "30000/1001" (29.97 fps)
+func parseFrameRate(rate string) (float64, error) {
+	rate = strings.TrimSpace(rate)
+	if num, den, ok := strings.Cut(rate, "/"); ok {
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid frame rate numerator: %s", rate)
+		}
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil || d == 0 {
+			return 0, fmt.Errorf("invalid frame rate denominator: %s", rate)
+		}
+		return n / d, nil
+	}
+
+	fps, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid frame rate: %s", rate)
+	}
+	return fps, nil
+}
+
+// shellQuote quotes an argument for POSIX shells if it contains anything
+// other than characters that are always safe unquoted
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+
+	safe := true
+	for _, r := range arg {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r)) {
+			safe = false
+			break
 		}
 	}
+	if safe {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
 
-	return true
+// formatShellCommand joins a command and its arguments into a single
+// shell-quoted command line
+func formatShellCommand(name string, args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, shellQuote(name))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
 }
@@ -0,0 +1,50 @@
+// cmd/util_test.go
+package cmd
+
+import "testing"
+
+func TestEstimateGIFSize(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		name                  string
+		width, height, frames int
+		colors                int
+		dithered              bool
+		min, max              int64
+	}{
+		{"480p clip, 10 seconds at 10 fps", 480, 270, 100, 256, true, 4 * mb, 6 * mb},
+		{"same clip without dithering", 480, 270, 100, 256, false, 3 * mb, 4 * mb},
+		{"same clip with 64 colors", 480, 270, 100, 64, true, 3 * mb, 4 * mb},
+		{"single frame", 480, 270, 1, 256, true, 80 * 1024, 90 * 1024},
+		{"tiny frame is mostly overhead", 1, 1, 1, 256, true, 1568, 1600},
+		{"colors clamped to 256", 480, 270, 100, 1000, true, 4 * mb, 6 * mb},
+		{"colors clamped to 2", 100, 100, 1, 0, false, 1300, 1400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateGIFSize(tt.width, tt.height, tt.frames, tt.colors, tt.dithered)
+			if got < tt.min || got > tt.max {
+				t.Errorf("EstimateGIFSize(%d, %d, %d, %d, %v) = %d, want %d-%d",
+					tt.width, tt.height, tt.frames, tt.colors, tt.dithered, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestEstimateGIFSizeEmpty(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height, frames int
+	}{
+		{"zero duration", 480, 270, 0},
+		{"zero width", 0, 270, 100},
+		{"zero height", 480, 0, 100},
+		{"zero size", 0, 0, 100},
+		{"negative frames", 480, 270, -1},
+	}
+	for _, tt := range tests {
+		if got := EstimateGIFSize(tt.width, tt.height, tt.frames, 256, true); got != 0 {
+			t.Errorf("%s: EstimateGIFSize(%d, %d, %d) = %d, want 0", tt.name, tt.width, tt.height, tt.frames, got)
+		}
+	}
+}
@@ -2,50 +2,155 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/Akashdeep-Patra/gif-maker/internal/ffmpeg"
+)
+
+// Build information, set at build time with
+// -ldflags "-X github.com/Akashdeep-Patra/gif-maker/cmd.Version=..."
+var (
+	Version = "dev"
+	Commit  = "dev"
+	Date    = "dev"
 )
 
+// Print version information as JSON
+var versionJSON bool
+
+// VersionInfo is the machine-readable output of `version --json`. The
+// FFmpeg fields are empty when that FFmpeg isn't available.
+type VersionInfo struct {
+	AppVersion     string `json:"app_version"`
+	Commit         string `json:"commit"`
+	Date           string `json:"date"`
+	EmbeddedFFmpeg string `json:"embedded_ffmpeg"`
+	SystemFFmpeg   string `json:"system_ffmpeg"`
+	Platform       string `json:"platform"`
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display version information",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		binaryName := ffmpeg.BinaryName()
+		embeddedVersion, embedded, embeddedErr := embeddedFFmpegVersion()
+		systemPath, systemErr := exec.LookPath("ffmpeg")
+		var systemVersion string
+		if systemErr == nil {
+			systemVersion, systemErr = ffmpegVersion(systemPath)
+		}
+
+		if versionJSON {
+			info := VersionInfo{
+				AppVersion:   Version,
+				Commit:       Commit,
+				Date:         Date,
+				SystemFFmpeg: systemVersion,
+				Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+			}
+			if embeddedErr == nil && embedded {
+				info.EmbeddedFFmpeg = embeddedVersion
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(info)
+		}
+
 		// Print application version
-		color.Green("GIF Maker v1.0.0")
+		color.Green("GIF Maker %s", displayVersion())
+		fmt.Printf("Commit: %s, built: %s\n", Commit, Date)
 		fmt.Println("A command-line tool to convert videos to GIFs")
 		fmt.Println("Source: https://github.com/akashdeep/gif-maker")
 		fmt.Println("")
 
+		// Report the FFmpeg that conversions actually use
+		if binaryName == "" {
+			binaryName = "none for this platform"
+		}
+		fmt.Printf("Platform: %s/%s (embedded binary: %s)\n", runtime.GOOS, runtime.GOARCH, binaryName)
+
+		switch {
+		case embeddedErr != nil:
+			color.Yellow("⚠️ Embedded FFmpeg: unavailable (%v)", embeddedErr)
+		case embedded:
+			color.Green("✅ Embedded FFmpeg: %s", embeddedVersion)
+		default:
+			color.Yellow("⚠️ Embedded FFmpeg: not bundled in this build, conversions use system FFmpeg")
+		}
+
 		// Check for FFmpeg installation
-		_, err := exec.LookPath("ffmpeg")
-		if err != nil {
+		if systemPath == "" {
+			if embeddedErr == nil && embedded {
+				fmt.Println("System FFmpeg: not found in PATH (not needed, the embedded binary is used)")
+				return nil
+			}
 			color.Red("❌ FFmpeg not found in PATH!")
 			fmt.Println("This tool requires FFmpeg to work. Please install it:")
 			fmt.Println("- MacOS: brew install ffmpeg")
 			fmt.Println("- Ubuntu/Debian: sudo apt install ffmpeg")
 			fmt.Println("- Windows: https://ffmpeg.org/download.html")
-			return
+			return nil
 		}
 
 		// Get FFmpeg version
-		ffmpegCmd := exec.Command("ffmpeg", "-version")
-		output, err := ffmpegCmd.Output()
-		if err != nil {
-			color.Yellow("⚠️ FFmpeg found but unable to determine version")
+		if systemErr != nil {
+			color.Yellow("⚠️ System FFmpeg found at %s but unable to determine version", systemPath)
 		} else {
-			outputStr := string(output)
-			lines := strings.Split(outputStr, "\n")
-			if len(lines) > 0 {
-				color.Green("✅ %s", lines[0])
-			}
+			color.Green("✅ System FFmpeg: %s (%s)", systemVersion, systemPath)
 		}
+		return nil
 	},
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print version information as JSON")
+
 	rootCmd.AddCommand(versionCmd)
 }
+
+// displayVersion returns the version with a v prefix for release builds
+func displayVersion() string {
+	if Version == "dev" || strings.HasPrefix(Version, "v") {
+		return Version
+	}
+	return "v" + Version
+}
+
+// ffmpegVersion returns the first line of `ffmpeg -version` for the binary
+func ffmpegVersion(path string) (string, error) {
+	output, err := exec.Command(path, "-version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.Split(string(output), "\n")[0]), nil
+}
+
+// embeddedFFmpegVersion extracts the embedded FFmpeg and returns its version.
+// The bool is false when the manager fell back to a system binary because
+// none is embedded for this platform.
+func embeddedFFmpegVersion() (string, bool, error) {
+	path, err := ffmpegManager.GetPath()
+	if err != nil {
+		return "", false, err
+	}
+	defer cleanupFFmpeg()
+
+	if !ffmpegManager.IsEmbedded() {
+		return "", false, nil
+	}
+
+	version, err := ffmpegVersion(path)
+	if err != nil {
+		return "", true, fmt.Errorf("could not run %s: %w", path, err)
+	}
+	return version, true, nil
+}
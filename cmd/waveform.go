@@ -0,0 +1,43 @@
+// cmd/waveform.go
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/Akashdeep-Patra/gif-maker/pkg/gifmaker"
+)
+
+// Supported audio visualizations
+var validWaveformModes = []string{gifmaker.WaveformWaves, gifmaker.WaveformSpectrum}
+
+// Supported places for the audio visualization
+var validWaveformPositions = []string{gifmaker.WaveformBottom, gifmaker.WaveformTop, gifmaker.WaveformCenter}
+
+// isValidWaveformMode checks if the audio visualization is supported
+func isValidWaveformMode(mode string) bool {
+	return slices.Contains(validWaveformModes, mode)
+}
+
+// isValidWaveformPosition checks if the visualization position is supported
+func isValidWaveformPosition(position string) bool {
+	return slices.Contains(validWaveformPositions, position)
+}
+
+// hasAudioStream uses ffprobe to check whether a file has an audio stream
+func hasAudioStream(path string) (bool, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=codec_name",
+		"-of", "csv=p=0",
+		path)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to probe audio streams: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
@@ -17,10 +17,16 @@ var embeddedBinaries embed.FS
 // Manager handles the extraction and usage of embedded FFmpeg binaries
 type Manager struct {
 	binariesDir     string
-	extractedPath   string
+	extractDir      string // Where to extract to instead of a new temp dir
+	tempDir         string // Parent of the new temp dir, "" for the OS default
+	extractedPath   string // Temp directory to remove on cleanup, if we created it
 	extractedBinary string
+	ownsBinary      bool // Whether extractedBinary was written by us
 	mu              sync.Mutex
 	extracted       bool
+
+	// Directories created for extractDir, deepest first
+	createdDirs []string
 }
 
 // NewManager creates a new FFmpeg manager
@@ -31,6 +37,24 @@ func NewManager() *Manager {
 	}
 }
 
+// SetExtractDir makes the manager extract FFmpeg into path instead of a
+// random temp directory. The directory is created if it doesn't exist.
+func (m *Manager) SetExtractDir(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.extractDir = path
+}
+
+// SetTempDir makes the manager create its temp directory under path
+// instead of the OS temp directory. SetExtractDir takes precedence.
+func (m *Manager) SetTempDir(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tempDir = path
+}
+
 // GetPath returns the path to the FFmpeg binary
 func (m *Manager) GetPath() (string, error) {
 	// Check if we've already extracted the binary
@@ -59,44 +83,108 @@ func (m *Manager) extractBinary() (string, error) {
 		return "", fmt.Errorf("unsupported platform: %s/%s", runtime.GOOS, runtime.GOARCH)
 	}
 
-	// Create a temporary directory for the extracted binary
-	tempDir, err := os.MkdirTemp("", "ffmpeg-extract")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
-	// Save the extraction path for cleanup later
-	m.extractedPath = tempDir
-
-	// Construct the embedded path and extract the binary
-	embeddedPath := filepath.Join(m.binariesDir, binaryName)
-
 	// Read the embedded binary
+	embeddedPath := filepath.Join(m.binariesDir, binaryName)
 	binaryData, err := embeddedBinaries.ReadFile(embeddedPath)
 	if err != nil {
 		// If the embedded binary isn't found, check for system installation
 		return m.findSystemFFmpeg()
 	}
 
-	// Determine the output path
-	outputPath := filepath.Join(tempDir, binaryName)
-	if runtime.GOOS != "windows" {
-		// On non-Windows platforms, don't include the extension
-		outputPath = filepath.Join(tempDir, "ffmpeg")
+	// Create a directory for the extracted binary
+	tempDir, err := m.prepareExtractDir()
+	if err != nil {
+		return "", err
 	}
 
-	// Write the binary to the temp directory
-	if err := os.WriteFile(outputPath, binaryData, 0755); err != nil {
-		return "", fmt.Errorf("failed to extract FFmpeg: %w", err)
+	outputPath, err := m.writeBinary(tempDir, binaryName, binaryData)
+	if err != nil {
+		return "", err
 	}
 
 	// Save the path and mark as extracted
 	m.extractedBinary = outputPath
+	m.ownsBinary = true
 	m.extracted = true
 
 	return outputPath, nil
 }
 
+// prepareExtractDir returns the directory to extract into, creating it if
+// needed. Only directories created here are recorded for removal, so a
+// user's existing directory is never deleted on cleanup.
+// Must be called with the mutex held
+func (m *Manager) prepareExtractDir() (string, error) {
+	if m.extractDir == "" {
+		tempDir, err := os.MkdirTemp(m.tempDir, "ffmpeg-extract")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		m.extractedPath = tempDir
+		return tempDir, nil
+	}
+
+	// Remember every missing directory on the way down, so cleanup can
+	// remove the parents MkdirAll creates as well
+	var missing []string
+	for dir := filepath.Clean(m.extractDir); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			break
+		}
+		missing = append(missing, dir)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	if len(missing) > 0 {
+		if err := os.MkdirAll(m.extractDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create extract directory: %w", err)
+		}
+		m.createdDirs = append(missing, m.createdDirs...)
+	}
+
+	return m.extractDir, nil
+}
+
+// writeBinary writes the FFmpeg binary into dir. A directory the user
+// chose gets a uniquely named file, so nothing already there is
+// overwritten or later removed by Cleanup.
+// Must be called with the mutex held
+func (m *Manager) writeBinary(dir, binaryName string, data []byte) (string, error) {
+	if m.extractDir == "" {
+		// On non-Windows platforms, don't include the extension
+		outputPath := filepath.Join(dir, binaryName)
+		if runtime.GOOS != "windows" {
+			outputPath = filepath.Join(dir, "ffmpeg")
+		}
+		if err := os.WriteFile(outputPath, data, 0755); err != nil {
+			return "", fmt.Errorf("failed to extract FFmpeg: %w", err)
+		}
+		return outputPath, nil
+	}
+
+	pattern := "ffmpeg-*"
+	if runtime.GOOS == "windows" {
+		pattern += ".exe"
+	}
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract FFmpeg: %w", err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0755)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to extract FFmpeg: %w", err)
+	}
+	return f.Name(), nil
+}
+
 // findSystemFFmpeg attempts to find a system-installed FFmpeg binary
 func (m *Manager) findSystemFFmpeg() (string, error) {
 	// Check if ffmpeg is available in PATH
@@ -105,27 +193,87 @@ func (m *Manager) findSystemFFmpeg() (string, error) {
 		return "", fmt.Errorf("FFmpeg not found in embedded binaries or system PATH")
 	}
 
-	// Use the system ffmpeg
+	// Use the system ffmpeg, removing any binary we extracted but couldn't use
+	if m.ownsBinary && m.extractedBinary != "" {
+		os.Remove(m.extractedBinary)
+	}
 	m.extractedBinary = path
+	m.ownsBinary = false
 	m.extracted = true
 
 	return path, nil
 }
 
+// FallbackToSystem switches to a system-installed FFmpeg, for when the
+// extracted binary can't be executed (e.g. on a noexec temp mount)
+func (m *Manager) FallbackToSystem() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.findSystemFFmpeg()
+}
+
+// IsEmbedded reports whether the current binary is the extracted embedded
+// one rather than a system installation
+func (m *Manager) IsEmbedded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ownsBinary
+}
+
+// ExtractedBinary returns the path of the binary extracted by the manager,
+// or "" if it uses a system installation
+func (m *Manager) ExtractedBinary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.ownsBinary {
+		return ""
+	}
+	return m.extractedBinary
+}
+
+// BinaryName returns the name of the embedded FFmpeg binary for the current
+// platform, or "" if the platform has none
+func BinaryName() string {
+	return getBinaryNameForPlatform()
+}
+
 // Cleanup removes the extracted files
 func (m *Manager) Cleanup() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// Remove only the binary from a directory we didn't create
+	if m.ownsBinary && m.extractedBinary != "" {
+		if err := os.Remove(m.extractedBinary); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clean up extracted files: %w", err)
+		}
+	}
+
 	if m.extractedPath != "" {
 		if err := os.RemoveAll(m.extractedPath); err != nil {
 			return fmt.Errorf("failed to clean up extracted files: %w", err)
 		}
-		m.extractedPath = ""
-		m.extractedBinary = ""
-		m.extracted = false
 	}
 
+	// Directories created for --extract-dir are removed deepest first, and
+	// only while empty, in case something else was put in them meanwhile
+	for _, dir := range m.createdDirs {
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+			if err := os.Remove(dir); err != nil {
+				return fmt.Errorf("failed to clean up extract directory: %w", err)
+			}
+		}
+	}
+
+	m.extractedPath = ""
+	m.createdDirs = nil
+	m.extractedBinary = ""
+	m.ownsBinary = false
+	m.extracted = false
+
 	return nil
 }
 
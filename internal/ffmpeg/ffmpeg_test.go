@@ -0,0 +1,123 @@
+// internal/ffmpeg/ffmpeg_test.go
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// extract runs the steps of extractBinary that don't depend on an
+// embedded binary for the current platform
+func extract(t *testing.T, m *Manager) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir, err := m.prepareExtractDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := m.writeBinary(dir, "ffmpeg-test", []byte("binary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.extractedBinary = path
+	m.ownsBinary = true
+	m.extracted = true
+	return path
+}
+
+func TestExtractDirKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(existing, []byte("user's own ffmpeg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager()
+	m.SetExtractDir(dir)
+	path := extract(t, m)
+	if path == existing || filepath.Dir(path) != dir {
+		t.Fatalf("extracted to %s, want a new file in %s", path, dir)
+	}
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(existing)
+	if err != nil || string(data) != "user's own ffmpeg" {
+		t.Errorf("existing file was changed or removed (read %q, error %v)", data, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("extracted binary %s was left behind", path)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("existing extract directory was removed: %v", err)
+	}
+}
+
+func TestExtractDirRemovesCreatedDirs(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "a", "b", "c")
+
+	m := NewManager()
+	m.SetExtractDir(dir)
+	path := extract(t, m)
+	if filepath.Dir(path) != dir {
+		t.Fatalf("extracted to %s, want a file in %s", path, dir)
+	}
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a")); !os.IsNotExist(err) {
+		t.Errorf("created directories were left behind (stat error %v)", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("directory that already existed was removed: %v", err)
+	}
+}
+
+func TestExtractDirKeepsCreatedDirsInUse(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "a", "b")
+
+	m := NewManager()
+	m.SetExtractDir(dir)
+	extract(t, m)
+
+	// Something else was saved next to the binary while it ran
+	other := filepath.Join(root, "a", "notes.txt")
+	if err := os.WriteFile(other, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("empty created directory %s was left behind (stat error %v)", dir, err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("file in a created directory was removed: %v", err)
+	}
+}
+
+func TestTempExtractDirRemoved(t *testing.T) {
+	parent := t.TempDir()
+
+	m := NewManager()
+	m.SetTempDir(parent)
+	path := extract(t, m)
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Errorf("temp directory %s was left behind (stat error %v)", filepath.Dir(path), err)
+	}
+	if _, err := os.Stat(parent); err != nil {
+		t.Errorf("--temp-dir itself was removed: %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+// Package gifopt shrinks GIFs in pure Go by merging duplicate frames, as an
+// alternative to external optimizers such as gifsicle.
+package gifopt
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+)
+
+// DefaultThreshold is the mean per-channel difference below which two
+// frames count as duplicates. It is low enough to only catch encoder
+// noise on otherwise static frames.
+const DefaultThreshold = 0.002
+
+// ErrTransparent is returned for GIFs with transparent pixels, whose frames
+// can't be merged without changing how they're disposed
+var ErrTransparent = errors.New("GIFs with transparent pixels are not supported")
+
+// Options controls the optimizer
+type Options struct {
+	// Threshold is the mean per-channel difference (0-1) up to which a
+	// frame counts as a duplicate of the one before it. 0 only merges
+	// identical frames.
+	Threshold float64
+}
+
+// Stats describes the result of optimizing a GIF
+type Stats struct {
+	FramesIn  int
+	FramesOut int
+	BytesIn   int64
+	BytesOut  int64
+}
+
+// OptimizeFile optimizes the GIF at path in place. The file is only
+// replaced if the result is smaller.
+func OptimizeFile(path string, opts Options) (Stats, error) {
+	var stats Stats
+
+	in, err := os.Open(path)
+	if err != nil {
+		return stats, err
+	}
+	g, err := gif.DecodeAll(in)
+	in.Close()
+	if err != nil {
+		return stats, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return stats, err
+	}
+	stats.BytesIn = info.Size()
+	stats.BytesOut = stats.BytesIn
+	stats.FramesIn = len(g.Image)
+	stats.FramesOut = stats.FramesIn
+
+	out, err := Dedupe(g, opts.Threshold)
+	if err != nil {
+		return stats, err
+	}
+	if len(out.Image) == len(g.Image) {
+		return stats, nil
+	}
+
+	// Write next to the original so the rename can't cross filesystems
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gifopt-*.gif")
+	if err != nil {
+		return stats, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gif.EncodeAll(tmp, out); err != nil {
+		tmp.Close()
+		return stats, fmt.Errorf("failed to encode optimized GIF: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return stats, err
+	}
+
+	info, err = os.Stat(tmp.Name())
+	if err != nil {
+		return stats, err
+	}
+	if info.Size() >= stats.BytesIn {
+		return stats, nil
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return stats, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	stats.FramesOut = len(out.Image)
+	stats.BytesOut = info.Size()
+	return stats, nil
+}
+
+// Dedupe returns a copy of g where every frame within threshold of the
+// previous kept frame is dropped and its delay added to that frame. Kept
+// frames only redraw the rectangle that changed, with unchanged pixels
+// inside it left transparent when the palette has room for that.
+func Dedupe(g *gif.GIF, threshold float64) (*gif.GIF, error) {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+
+	global, _ := g.Config.ColorModel.(color.Palette)
+	out := &gif.GIF{
+		LoopCount:       g.LoopCount,
+		BackgroundIndex: g.BackgroundIndex,
+		Config: image.Config{
+			ColorModel: withTransparent(global),
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+
+	canvas := image.NewRGBA(bounds)
+	kept := image.NewRGBA(bounds)
+	var saved *image.RGBA
+
+	for i, frame := range g.Image {
+		// Undo the previous frame as its disposal method asks
+		if i > 0 {
+			prev := g.Image[i-1]
+			switch disposal(g, i-1) {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, prev.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				if saved != nil {
+					copy(canvas.Pix, saved.Pix)
+				}
+			}
+		}
+		if disposal(g, i) == gif.DisposalPrevious {
+			if saved == nil {
+				saved = image.NewRGBA(bounds)
+			}
+			copy(saved.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		if hasTransparency(canvas) {
+			return nil, ErrTransparent
+		}
+
+		delay := 0
+		if i < len(g.Delay) {
+			delay = g.Delay[i]
+		}
+
+		if i > 0 && difference(canvas, kept) <= threshold {
+			out.Delay[len(out.Delay)-1] += delay
+			continue
+		}
+
+		palette := frame.Palette
+		if palette == nil {
+			palette = global
+		}
+		first := i == 0
+		out.Image = append(out.Image, redraw(canvas, kept, withTransparent(palette), first))
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, gif.DisposalNone)
+		copy(kept.Pix, canvas.Pix)
+	}
+
+	return out, nil
+}
+
+// disposal returns the disposal method of frame i
+func disposal(g *gif.GIF, i int) byte {
+	if i < len(g.Disposal) {
+		return g.Disposal[i]
+	}
+	return gif.DisposalNone
+}
+
+// withTransparent returns the palette with a transparent entry added if it
+// has none and there is room for one
+func withTransparent(p color.Palette) color.Palette {
+	if p == nil || len(p) >= 256 {
+		return p
+	}
+	for _, c := range p {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return p
+		}
+	}
+	return append(append(color.Palette(nil), p...), color.RGBA{})
+}
+
+// transparentIndex returns the index of the palette's transparent entry,
+// or -1 if it has none
+func transparentIndex(p color.Palette) int {
+	for i, c := range p {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasTransparency reports whether any pixel of img is not fully opaque
+func hasTransparency(img *image.RGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 0xff {
+			return true
+		}
+	}
+	return false
+}
+
+// difference returns the mean absolute per-channel difference between two
+// images of the same size, from 0 (identical) to 1
+func difference(a, b *image.RGBA) float64 {
+	if len(a.Pix) == 0 {
+		return 0
+	}
+	var sum int64
+	for i := range a.Pix {
+		if i%4 == 3 {
+			continue
+		}
+		d := int64(a.Pix[i]) - int64(b.Pix[i])
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	channels := int64(len(a.Pix)) / 4 * 3
+	return float64(sum) / float64(channels*255)
+}
+
+// changedRect returns the smallest rectangle containing every pixel that
+// differs between a and b
+func changedRect(a, b *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := a.PixOffset(bounds.Min.X, y)
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			o := row + (x-bounds.Min.X)*4
+			if a.Pix[o] != b.Pix[o] || a.Pix[o+1] != b.Pix[o+1] || a.Pix[o+2] != b.Pix[o+2] {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+// redraw builds the frame that turns the previous kept image into canvas.
+// The first frame covers everything; later ones only the changed rectangle.
+func redraw(canvas, previous *image.RGBA, palette color.Palette, first bool) *image.Paletted {
+	rect := canvas.Bounds()
+	if !first {
+		rect = changedRect(canvas, previous)
+		if rect.Empty() {
+			// Nothing visible changed; GIF frames can't be empty, so draw one pixel
+			origin := canvas.Bounds().Min
+			rect = image.Rectangle{Min: origin, Max: origin.Add(image.Pt(1, 1))}
+		}
+	}
+
+	img := image.NewPaletted(rect, palette)
+	transparent := transparentIndex(palette)
+	cache := make(map[color.RGBA]uint8)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			o := canvas.PixOffset(x, y)
+			c := color.RGBA{canvas.Pix[o], canvas.Pix[o+1], canvas.Pix[o+2], 0xff}
+
+			// Unchanged pixels show through from the frame below
+			if !first && transparent >= 0 && previous.Pix[o] == c.R && previous.Pix[o+1] == c.G && previous.Pix[o+2] == c.B {
+				img.Pix[img.PixOffset(x, y)] = uint8(transparent)
+				continue
+			}
+
+			idx, ok := cache[c]
+			if !ok {
+				idx = uint8(palette.Index(c))
+				cache[c] = idx
+			}
+			img.Pix[img.PixOffset(x, y)] = idx
+		}
+	}
+	return img
+}
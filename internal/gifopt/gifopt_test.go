@@ -0,0 +1,192 @@
+// internal/gifopt/gifopt_test.go
+package gifopt
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testPalette = color.Palette{
+	color.RGBA{0x00, 0x00, 0x00, 0xff},
+	color.RGBA{0xff, 0xff, 0xff, 0xff},
+	color.RGBA{0xff, 0x00, 0x00, 0xff},
+	color.RGBA{0x00, 0x00, 0xff, 0xff},
+}
+
+// solidFrame returns a w x h frame filled with one palette entry
+func solidFrame(w, h int, index uint8, palette color.Palette) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, w, h), palette)
+	for i := range img.Pix {
+		img.Pix[i] = index
+	}
+	return img
+}
+
+// newGIF builds a GIF from frames and their delays
+func newGIF(frames []*image.Paletted, delays []int) *gif.GIF {
+	bounds := frames[0].Bounds()
+	return &gif.GIF{
+		Image: frames,
+		Delay: delays,
+		Config: image.Config{
+			ColorModel: frames[0].Palette,
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+}
+
+func TestDedupeMergesIdenticalFrames(t *testing.T) {
+	g := newGIF([]*image.Paletted{
+		solidFrame(8, 8, 0, testPalette),
+		solidFrame(8, 8, 0, testPalette),
+		solidFrame(8, 8, 0, testPalette),
+		solidFrame(8, 8, 1, testPalette),
+	}, []int{10, 20, 5, 7})
+
+	out, err := Dedupe(g, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Image) != 2 {
+		t.Fatalf("got %d frames, want 2", len(out.Image))
+	}
+	if want := []int{35, 7}; out.Delay[0] != want[0] || out.Delay[1] != want[1] {
+		t.Errorf("delays = %v, want %v", out.Delay, want)
+	}
+}
+
+func TestDedupeThreshold(t *testing.T) {
+	// One pixel out of 100 flips from black to white, a mean difference
+	// of 0.01
+	changed := solidFrame(10, 10, 0, testPalette)
+	changed.Pix[0] = 1
+
+	tests := []struct {
+		name      string
+		threshold float64
+		want      int
+	}{
+		{"within the threshold", 0.02, 1},
+		{"exactly the threshold", 0.01, 1},
+		{"above the threshold", 0.005, 2},
+		{"identical only", 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGIF([]*image.Paletted{solidFrame(10, 10, 0, testPalette), changed}, []int{10, 10})
+			out, err := Dedupe(g, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out.Image) != tt.want {
+				t.Errorf("got %d frames, want %d", len(out.Image), tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupeTransparent(t *testing.T) {
+	palette := append(color.Palette{color.RGBA{}}, testPalette...)
+	g := newGIF([]*image.Paletted{
+		solidFrame(4, 4, 0, palette),
+		solidFrame(4, 4, 1, palette),
+	}, []int{10, 10})
+
+	if _, err := Dedupe(g, 0); !errors.Is(err, ErrTransparent) {
+		t.Errorf("Dedupe error = %v, want ErrTransparent", err)
+	}
+}
+
+// writeGIF encodes g to a file in a temp directory and returns its path
+func writeGIF(t *testing.T, g *gif.GIF) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "in.gif")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// noiseFrame returns a w x h frame of pseudo-random palette entries, which
+// LZW can't compress much
+func noiseFrame(w, h int, seed uint32, palette color.Palette) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, w, h), palette)
+	state := seed
+	for i := range img.Pix {
+		state = state*1664525 + 1013904223
+		img.Pix[i] = uint8(state>>24) % uint8(len(palette))
+	}
+	return img
+}
+
+func TestOptimizeFileKeepsLargerResult(t *testing.T) {
+	// The duplicate is a single pixel, so dropping it saves less than the
+	// noise frame after it costs once its unchanged pixels are redrawn as
+	// transparent, which adds a fifth symbol for LZW to encode
+	first := noiseFrame(200, 200, 1, testPalette)
+	dup := image.NewPaletted(image.Rect(0, 0, 1, 1), testPalette)
+	dup.Pix[0] = first.Pix[0]
+	last := noiseFrame(200, 200, 2, testPalette)
+	path := writeGIF(t, newGIF([]*image.Paletted{first, dup, last}, []int{10, 10, 10}))
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := OptimizeFile(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(before, after) {
+		t.Error("file was rewritten although the result wasn't smaller")
+	}
+	if stats.FramesOut != stats.FramesIn || stats.BytesOut != stats.BytesIn {
+		t.Errorf("stats = %+v, want the input unchanged", stats)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %d entries in the directory", len(entries))
+	}
+}
+
+func TestOptimizeFileShrinks(t *testing.T) {
+	path := writeGIF(t, newGIF([]*image.Paletted{
+		noiseFrame(64, 64, 1, testPalette),
+		noiseFrame(64, 64, 1, testPalette),
+		solidFrame(64, 64, 1, testPalette),
+	}, []int{10, 10, 10}))
+
+	stats, err := OptimizeFile(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.FramesIn != 3 || stats.FramesOut != 2 {
+		t.Errorf("frames %d -> %d, want 3 -> 2", stats.FramesIn, stats.FramesOut)
+	}
+	if stats.BytesOut >= stats.BytesIn {
+		t.Errorf("bytes %d -> %d, want smaller", stats.BytesIn, stats.BytesOut)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != stats.BytesOut {
+		t.Errorf("file is %d bytes, stats say %d", info.Size(), stats.BytesOut)
+	}
+}
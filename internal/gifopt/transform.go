@@ -0,0 +1,422 @@
+package gifopt
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Transform describes changes to an existing GIF that keep its palettes,
+// so it isn't quantized a second time
+type Transform struct {
+	// Start and Duration trim the GIF, in seconds. A Duration of 0 keeps
+	// everything after Start.
+	Start    float64
+	Duration float64
+
+	// Width and Height resize the GIF. If only one is set the other keeps
+	// the aspect ratio; if both are set the GIF is fitted inside them.
+	Width  int
+	Height int
+
+	// Colors is the most colors any palette may keep. 0 keeps them all.
+	Colors int
+}
+
+// TransformFile applies t to the GIF at src and writes the result to dst,
+// which may be the same file
+func TransformFile(src, dst string, t Transform) (Stats, error) {
+	var stats Stats
+
+	in, err := os.Open(src)
+	if err != nil {
+		return stats, err
+	}
+	g, err := gif.DecodeAll(in)
+	in.Close()
+	if err != nil {
+		return stats, fmt.Errorf("failed to decode %s: %w", src, err)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return stats, err
+	}
+	stats.BytesIn = info.Size()
+	stats.FramesIn = len(g.Image)
+
+	if t.Start > 0 || t.Duration > 0 {
+		g = Trim(g, t.Start, t.Duration)
+		if len(g.Image) == 0 {
+			return stats, fmt.Errorf("no frames between %gs and %gs", t.Start, t.Start+t.Duration)
+		}
+	}
+	if width, height := FitSize(g.Config.Width, g.Config.Height, t.Width, t.Height); width != g.Config.Width || height != g.Config.Height {
+		g = Resize(g, width, height)
+	}
+	if t.Colors > 0 {
+		g = ReduceColors(g, t.Colors)
+	}
+
+	// Write next to the destination so the rename can't cross filesystems
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".gifopt-*.gif")
+	if err != nil {
+		return stats, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gif.EncodeAll(tmp, g); err != nil {
+		tmp.Close()
+		return stats, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return stats, err
+	}
+
+	info, err = os.Stat(tmp.Name())
+	if err != nil {
+		return stats, err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return stats, fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	stats.FramesOut = len(g.Image)
+	stats.BytesOut = info.Size()
+	return stats, nil
+}
+
+// FitSize returns the size a srcW x srcH image is resized to for a
+// requested width and height, where 0 means unset
+func FitSize(srcW, srcH, width, height int) (int, int) {
+	if srcW <= 0 || srcH <= 0 {
+		return srcW, srcH
+	}
+	switch {
+	case width > 0 && height > 0:
+		if width*srcH > height*srcW {
+			width = max(1, (srcW*height+srcH/2)/srcH)
+		} else {
+			height = max(1, (srcH*width+srcW/2)/srcW)
+		}
+		return width, height
+	case width > 0:
+		return width, max(1, (srcH*width+srcW/2)/srcW)
+	case height > 0:
+		return max(1, (srcW*height+srcH/2)/srcH), height
+	default:
+		return srcW, srcH
+	}
+}
+
+// Trim returns a copy of g with only the frames that start within
+// [start, start+duration) seconds. A duration of 0 keeps everything after
+// start. If frames are dropped from the beginning, the first kept frame is
+// redrawn in full so it doesn't depend on the dropped ones.
+func Trim(g *gif.GIF, start, duration float64) *gif.GIF {
+	startCS := int(start*100 + 0.5)
+	endCS := -1
+	if duration > 0 {
+		endCS = startCS + int(duration*100+0.5)
+	}
+
+	out := *g
+	out.Image, out.Delay, out.Disposal = nil, nil, nil
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	var saved *image.RGBA
+
+	t := 0
+	for i, frame := range g.Image {
+		delay := 0
+		if i < len(g.Delay) {
+			delay = g.Delay[i]
+		}
+		frameStart := t
+		t += delay
+
+		if frameStart < startCS || (endCS >= 0 && frameStart >= endCS) {
+			// Keep track of what a dropped leading frame leaves on screen
+			if frameStart < startCS {
+				composite(g, i, canvas, &saved)
+			}
+			continue
+		}
+
+		if len(out.Image) == 0 && i > 0 {
+			composite(g, i, canvas, &saved)
+			frame = flatten(canvas, frame.Palette)
+		}
+		out.Image = append(out.Image, frame)
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, disposal(g, i))
+	}
+	return &out
+}
+
+// composite draws frame i onto canvas, first undoing frame i-1 as its
+// disposal method asks
+func composite(g *gif.GIF, i int, canvas *image.RGBA, saved **image.RGBA) {
+	if i > 0 {
+		prev := g.Image[i-1]
+		switch disposal(g, i-1) {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, prev.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			if *saved != nil {
+				copy(canvas.Pix, (*saved).Pix)
+			}
+		}
+	}
+	if disposal(g, i) == gif.DisposalPrevious {
+		if *saved == nil {
+			*saved = image.NewRGBA(canvas.Bounds())
+		}
+		copy((*saved).Pix, canvas.Pix)
+	}
+	frame := g.Image[i]
+	draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+}
+
+// flatten turns the canvas into a full frame using the given palette
+func flatten(canvas *image.RGBA, palette color.Palette) *image.Paletted {
+	img := image.NewPaletted(canvas.Bounds(), palette)
+	draw.Draw(img, img.Bounds(), canvas, canvas.Bounds().Min, draw.Src)
+	return img
+}
+
+// Resize returns a copy of g scaled to width x height with nearest-neighbor
+// sampling, so every frame keeps its palette and no colors are re-quantized.
+func Resize(g *gif.GIF, width, height int) *gif.GIF {
+	srcW, srcH := g.Config.Width, g.Config.Height
+	if srcW <= 0 || srcH <= 0 || width <= 0 || height <= 0 || (width == srcW && height == srcH) {
+		return g
+	}
+
+	// Scaled frame edges are rounded the same way the pixels are sampled,
+	// so frames that tile the canvas still tile it after scaling
+	scaleX := func(x int) int { return (x*width + srcW - 1) / srcW }
+	scaleY := func(y int) int { return (y*height + srcH - 1) / srcH }
+
+	out := *g
+	out.Config.Width, out.Config.Height = width, height
+	out.Image = make([]*image.Paletted, len(g.Image))
+	for i, frame := range g.Image {
+		b := frame.Bounds()
+		rect := image.Rect(scaleX(b.Min.X), scaleY(b.Min.Y), scaleX(b.Max.X), scaleY(b.Max.Y))
+		if rect.Empty() {
+			// A frame smaller than a scaled pixel still has to exist
+			rect = image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+1, rect.Min.Y+1).Intersect(image.Rect(0, 0, width, height))
+		}
+
+		img := image.NewPaletted(rect, frame.Palette)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			sy := min(y*srcH/height, b.Max.Y-1)
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				sx := min(x*srcW/width, b.Max.X-1)
+				img.Pix[img.PixOffset(x, y)] = frame.ColorIndexAt(max(sx, b.Min.X), max(sy, b.Min.Y))
+			}
+		}
+		out.Image[i] = img
+	}
+	return &out
+}
+
+// ReduceColors returns a copy of g where every palette has at most n
+// colors. Similar palette entries are merged with a median cut weighted by
+// how many pixels use them, and pixels are remapped by index, so the
+// original quantization and dithering are kept.
+func ReduceColors(g *gif.GIF, n int) *gif.GIF {
+	if n < 2 {
+		n = 2
+	}
+
+	// Count how many pixels use each entry of each distinct palette
+	type reduction struct {
+		palette color.Palette
+		counts  []int
+		remap   []uint8
+		reduced color.Palette
+	}
+	reductions := make(map[string]*reduction)
+	keys := make([]string, len(g.Image))
+	for i, frame := range g.Image {
+		key := paletteKey(frame.Palette)
+		keys[i] = key
+		r, ok := reductions[key]
+		if !ok {
+			r = &reduction{palette: frame.Palette, counts: make([]int, len(frame.Palette))}
+			reductions[key] = r
+		}
+		for _, idx := range frame.Pix {
+			if int(idx) < len(r.counts) {
+				r.counts[idx]++
+			}
+		}
+	}
+
+	for _, r := range reductions {
+		r.reduced, r.remap = medianCut(r.palette, r.counts, n)
+	}
+
+	out := *g
+	if global, ok := g.Config.ColorModel.(color.Palette); ok {
+		if r, ok := reductions[paletteKey(global)]; ok {
+			out.Config.ColorModel = r.reduced
+		} else {
+			counts := make([]int, len(global))
+			reduced, _ := medianCut(global, counts, n)
+			out.Config.ColorModel = reduced
+		}
+	}
+
+	out.Image = make([]*image.Paletted, len(g.Image))
+	for i, frame := range g.Image {
+		r := reductions[keys[i]]
+		img := image.NewPaletted(frame.Bounds(), r.reduced)
+		for j, idx := range frame.Pix {
+			if int(idx) < len(r.remap) {
+				img.Pix[j] = r.remap[idx]
+			}
+		}
+		out.Image[i] = img
+	}
+	return &out
+}
+
+// paletteKey identifies a palette by its colors
+func paletteKey(p color.Palette) string {
+	key := make([]byte, 0, len(p)*4)
+	for _, c := range p {
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		key = append(key, rgba.R, rgba.G, rgba.B, rgba.A)
+	}
+	return string(key)
+}
+
+// paletteEntry is a palette color in a median cut box
+type paletteEntry struct {
+	index  int
+	color  color.RGBA
+	weight int
+}
+
+// medianCut merges the opaque entries of p into at most n colors. It
+// returns the new palette and, for every old index, its new index. A
+// transparent entry is kept as is.
+func medianCut(p color.Palette, counts []int, n int) (color.Palette, []uint8) {
+	remap := make([]uint8, len(p))
+	if len(p) <= n {
+		for i := range remap {
+			remap[i] = uint8(i)
+		}
+		return p, remap
+	}
+
+	var entries []paletteEntry
+	transparent := -1
+	for i, c := range p {
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		if rgba.A == 0 {
+			transparent = i
+			continue
+		}
+		// Unused entries still need somewhere to go, but shouldn't pull
+		// the merged colors towards them
+		entries = append(entries, paletteEntry{index: i, color: rgba, weight: counts[i] + 1})
+	}
+
+	opaque := n
+	if transparent >= 0 {
+		opaque--
+	}
+	boxes := [][]paletteEntry{entries}
+	for len(boxes) < opaque {
+		// Split the box with the widest channel range that can be split
+		best, bestRange, bestChannel := -1, -1, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			channel, r := widestChannel(box)
+			if r > bestRange {
+				best, bestRange, bestChannel = i, r, channel
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(a, b int) bool {
+			return channelValue(box[a].color, bestChannel) < channelValue(box[b].color, bestChannel)
+		})
+		total := 0
+		for _, e := range box {
+			total += e.weight
+		}
+		split, acc := 1, 0
+		for i, e := range box[:len(box)-1] {
+			acc += e.weight
+			split = i + 1
+			if acc*2 >= total {
+				break
+			}
+		}
+		boxes[best] = box[:split]
+		boxes = append(boxes, box[split:])
+	}
+
+	var reduced color.Palette
+	for _, box := range boxes {
+		var r, g, b, total int
+		for _, e := range box {
+			r += int(e.color.R) * e.weight
+			g += int(e.color.G) * e.weight
+			b += int(e.color.B) * e.weight
+			total += e.weight
+		}
+		idx := uint8(len(reduced))
+		reduced = append(reduced, color.RGBA{uint8(r / total), uint8(g / total), uint8(b / total), 0xff})
+		for _, e := range box {
+			remap[e.index] = idx
+		}
+	}
+	if transparent >= 0 {
+		remap[transparent] = uint8(len(reduced))
+		reduced = append(reduced, color.RGBA{})
+	}
+	return reduced, remap
+}
+
+// widestChannel returns the RGB channel with the largest range in a box
+func widestChannel(box []paletteEntry) (channel, width int) {
+	for c := 0; c < 3; c++ {
+		lo, hi := 255, 0
+		for _, e := range box {
+			v := channelValue(e.color, c)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		if hi-lo > width {
+			channel, width = c, hi-lo
+		}
+	}
+	return channel, width
+}
+
+// channelValue returns the red (0), green (1) or blue (2) value of c
+func channelValue(c color.RGBA, channel int) int {
+	switch channel {
+	case 0:
+		return int(c.R)
+	case 1:
+		return int(c.G)
+	default:
+		return int(c.B)
+	}
+}
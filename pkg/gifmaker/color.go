@@ -0,0 +1,59 @@
+// pkg/gifmaker/color.go
+package gifmaker
+
+import "fmt"
+
+// Source color ranges. Most video is limited range; if the source doesn't
+// say which it uses, FFmpeg guesses, and a wrong guess makes the GIF look
+// washed out (tv read as pc) or crushed and oversaturated (pc read as tv).
+const (
+	ColorRangeTV = "tv" // Limited range, 16-235
+	ColorRangePC = "pc" // Full range, 0-255
+)
+
+// Tone mapping operators for bringing HDR sources down to SDR
+const (
+	TonemapHable    = "hable"    // Filmic curve that keeps highlight detail
+	TonemapMobius   = "mobius"   // Keeps in-range colors exact, compresses the rest
+	TonemapReinhard = "reinhard" // Simple curve, brighter overall
+	TonemapClip     = "clip"     // Hard clip, only for barely-HDR sources
+)
+
+// HDR transfer characteristics as ffprobe reports them
+const (
+	TransferPQ  = "smpte2084"    // HDR10 and Dolby Vision
+	TransferHLG = "arib-std-b67" // Hybrid log-gamma, common on phones
+)
+
+// IsHDRTransfer reports whether an ffprobe color_transfer value is HDR
+func IsHDRTransfer(transfer string) bool {
+	return transfer == TransferPQ || transfer == TransferHLG
+}
+
+// ColorFilter returns the filters that fix up the source's colors before
+// anything else, or "" if there is nothing to do. colorRange overrides
+// the range the source is read as; tonemap, a Tonemap* constant, converts
+// HDR to SDR with zscale, which needs an FFmpeg built with libzimg.
+func ColorFilter(colorRange, tonemap string) string {
+	if tonemap != "" {
+		// Linearize, map the brightness down in float, then convert to
+		// BT.709 full range for the palette
+		in := "zscale=t=linear:npl=100"
+		if colorRange != "" {
+			in = fmt.Sprintf("zscale=rangein=%s:t=linear:npl=100", rangeName(colorRange))
+		}
+		return fmt.Sprintf("%s,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=%s:desat=0,zscale=t=bt709:m=bt709:r=full,format=rgb24", in, tonemap)
+	}
+	if colorRange != "" {
+		return fmt.Sprintf("scale=in_range=%s:out_range=pc", colorRange)
+	}
+	return ""
+}
+
+// rangeName returns zscale's name for a ColorRange* constant
+func rangeName(colorRange string) string {
+	if colorRange == ColorRangeTV {
+		return "limited"
+	}
+	return "full"
+}
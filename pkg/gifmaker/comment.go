@@ -0,0 +1,177 @@
+// pkg/gifmaker/comment.go
+package gifmaker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// GIF block markers
+const (
+	gifExtension      = 0x21
+	gifImageSeparator = 0x2C
+	gifTrailer        = 0x3B
+	gifCommentLabel   = 0xFE
+)
+
+// Size of the header and logical screen descriptor at the start of a GIF
+const gifScreenHeaderSize = 13
+
+// AddGIFComment adds a comment extension with text to the GIF at path,
+// right after its global color table. Comments don't change how the GIF
+// looks; decoders that don't show them skip them.
+func AddGIFComment(path, text string) error {
+	if text == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(data) < gifScreenHeaderSize || !bytes.HasPrefix(data, []byte("GIF8")) {
+		return fmt.Errorf("%s is not a GIF", path)
+	}
+
+	// The global color table follows the screen descriptor if its flag is set
+	offset := gifScreenHeaderSize
+	if flags := data[10]; flags&0x80 != 0 {
+		offset += 3 << (flags&0x07 + 1)
+	}
+	if offset > len(data) {
+		return fmt.Errorf("%s is truncated", path)
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data) + len(text) + len(text)/255 + 4)
+	out.Write(data[:offset])
+	out.Write(commentExtension(text))
+	out.Write(data[offset:])
+
+	// Write next to the GIF and rename, so a failure leaves it intact and
+	// the rename can't cross filesystems
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".comment-*.gif")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(out.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// commentExtension encodes text as a comment extension, split into data
+// sub-blocks of at most 255 bytes
+func commentExtension(text string) []byte {
+	ext := []byte{gifExtension, gifCommentLabel}
+	for rest := []byte(text); len(rest) > 0; {
+		n := min(len(rest), 255)
+		ext = append(ext, byte(n))
+		ext = append(ext, rest[:n]...)
+		rest = rest[n:]
+	}
+	return append(ext, 0)
+}
+
+// ReadGIFComments returns the text of every comment extension in a GIF, in
+// the order they appear
+func ReadGIFComments(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, gifScreenHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("reading GIF header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte("GIF8")) {
+		return nil, errors.New("not a GIF")
+	}
+	if flags := header[10]; flags&0x80 != 0 {
+		if _, err := br.Discard(3 << (flags&0x07 + 1)); err != nil {
+			return nil, fmt.Errorf("reading global color table: %w", err)
+		}
+	}
+
+	var comments []string
+	for {
+		marker, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading GIF block: %w", err)
+		}
+		switch marker {
+		case gifTrailer:
+			return comments, nil
+
+		case gifExtension:
+			label, err := br.ReadByte()
+			if err != nil {
+				return nil, fmt.Errorf("reading GIF extension: %w", err)
+			}
+			data, err := readSubBlocks(br)
+			if err != nil {
+				return nil, err
+			}
+			if label == gifCommentLabel {
+				comments = append(comments, string(data))
+			}
+
+		case gifImageSeparator:
+			// Position, size and flags, then the local color table
+			descriptor := make([]byte, 9)
+			if _, err := io.ReadFull(br, descriptor); err != nil {
+				return nil, fmt.Errorf("reading image descriptor: %w", err)
+			}
+			if flags := descriptor[8]; flags&0x80 != 0 {
+				if _, err := br.Discard(3 << (flags&0x07 + 1)); err != nil {
+					return nil, fmt.Errorf("reading local color table: %w", err)
+				}
+			}
+			// LZW minimum code size, then the image data
+			if _, err := br.ReadByte(); err != nil {
+				return nil, fmt.Errorf("reading image data: %w", err)
+			}
+			if _, err := readSubBlocks(br); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown GIF block 0x%02x", marker)
+		}
+	}
+}
+
+// readSubBlocks reads data sub-blocks up to the terminating empty one and
+// returns their joined contents
+func readSubBlocks(br *bufio.Reader) ([]byte, error) {
+	var data []byte
+	for {
+		size, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading GIF data block: %w", err)
+		}
+		if size == 0 {
+			return data, nil
+		}
+		block := make([]byte, size)
+		if _, err := io.ReadFull(br, block); err != nil {
+			return nil, fmt.Errorf("reading GIF data block: %w", err)
+		}
+		data = append(data, block...)
+	}
+}
@@ -0,0 +1,124 @@
+// pkg/gifmaker/comment_test.go
+package gifmaker
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeTestGIF encodes a small three-frame GIF to a temp file and returns
+// its path. With globalPalette set the palette goes in the global color
+// table, otherwise each frame carries a local one.
+func writeTestGIF(t *testing.T, globalPalette bool) string {
+	t.Helper()
+	palette := color.Palette{color.Black, color.White, color.RGBA{0xff, 0, 0, 0xff}}
+	g := &gif.GIF{}
+	for i := 0; i < 3; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		for j := range frame.Pix {
+			frame.Pix[j] = uint8((i + j) % len(palette))
+		}
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+	if globalPalette {
+		g.Config = image.Config{ColorModel: palette, Width: 4, Height: 4}
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "out.gif")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAddGIFComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+	}{
+		{"short comment", []string{"made with gif-maker"}},
+		{"one full sub-block", []string{strings.Repeat("a", 255)}},
+		{"several sub-blocks", []string{strings.Repeat("0123456789", 60)}},
+		{"two comments", []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		for _, global := range []bool{true, false} {
+			name := tt.name + " with a local palette"
+			if global {
+				name = tt.name + " with a global palette"
+			}
+			t.Run(name, func(t *testing.T) {
+				path := writeTestGIF(t, global)
+				for _, comment := range tt.comments {
+					if err := AddGIFComment(path, comment); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Each comment is inserted right after the color table, so
+				// the last one added comes first
+				want := slices.Clone(tt.comments)
+				slices.Reverse(want)
+				got, err := ReadGIFComments(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("ReadGIFComments: %v", err)
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("ReadGIFComments() = %q, want %q", got, want)
+				}
+
+				g, err := gif.DecodeAll(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("gif.DecodeAll after adding a comment: %v", err)
+				}
+				if len(g.Image) != 3 {
+					t.Errorf("got %d frames, want 3", len(g.Image))
+				}
+			})
+		}
+	}
+}
+
+func TestAddGIFCommentEmpty(t *testing.T) {
+	path := writeTestGIF(t, true)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddGIFComment(path, ""); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("an empty comment changed the file")
+	}
+}
+
+func TestAddGIFCommentNotGIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	if err := os.WriteFile(path, []byte("not a gif at all"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddGIFComment(path, "comment"); err == nil {
+		t.Error("AddGIFComment on a non-GIF file succeeded")
+	}
+}
@@ -0,0 +1,141 @@
+// pkg/gifmaker/errors.go
+package gifmaker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Most FFmpeg errors we want to show are buried among the banner, stream
+// info and progress lines. These fragments mark the lines that matter.
+var ffmpegErrorPatterns = []string{
+	"Error",
+	"error",
+	"Invalid",
+	"invalid",
+	"No such",
+	"Conversion failed",
+	"Failed",
+	"not found",
+	"Unknown",
+	"Unrecognized",
+	"Permission denied",
+	"does not exist",
+	"Cannot",
+	"Could not",
+	"could not",
+}
+
+// Number of lines of FFmpeg output to show when no error line stands out
+const maxErrorLines = 5
+
+// How much of the end of FFmpeg's log is kept for FFmpegError. A long
+// conversion at -loglevel verbose can log far more than is worth holding on
+// to, and the error is always near the end.
+const maxErrorOutput = 64 * 1024
+
+// FFmpegError is returned when FFmpeg exits with an error. Error() shows
+// the lines of the log that describe the problem; Output has the end of the
+// log, up to the last 64 KiB.
+type FFmpegError struct {
+	Err    error // The exit error
+	Lines  []string
+	Output string
+}
+
+func (e *FFmpegError) Error() string {
+	if len(e.Lines) == 0 {
+		return fmt.Sprintf("FFmpeg conversion failed: %v", e.Err)
+	}
+	return fmt.Sprintf("FFmpeg conversion failed: %v\n%s", e.Err, strings.Join(e.Lines, "\n"))
+}
+
+func (e *FFmpegError) Unwrap() error {
+	return e.Err
+}
+
+// Tail returns the last n bytes of the FFmpeg log
+func (e *FFmpegError) Tail(n int) string {
+	if len(e.Output) <= n {
+		return e.Output
+	}
+	return e.Output[len(e.Output)-n:]
+}
+
+// ErrorLines picks the lines of an FFmpeg log that describe what went
+// wrong. If none match a known error pattern, the last few lines are
+// returned instead.
+func ErrorLines(output string) []string {
+	var lines, matched []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		// Progress updates end with \r and overwrite each other
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+
+		// The build configuration lists flags like --enable-error-resilience
+		if strings.HasPrefix(line, "configuration:") || seen[line] {
+			continue
+		}
+		for _, pattern := range ffmpegErrorPatterns {
+			if strings.Contains(line, pattern) {
+				matched = append(matched, line)
+				seen[line] = true
+				break
+			}
+		}
+	}
+
+	if len(matched) == 0 {
+		matched = lines
+	}
+	if len(matched) > maxErrorLines {
+		matched = matched[len(matched)-maxErrorLines:]
+	}
+	return matched
+}
+
+// tailBuffer is an io.Writer that keeps only the last len(buf) bytes
+// written to it
+type tailBuffer struct {
+	buf  []byte
+	next int  // Where the next byte goes
+	full bool // Whether buf has wrapped around
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{buf: make([]byte, size)}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= len(b.buf) {
+		copy(b.buf, p[n-len(b.buf):])
+		b.next, b.full = 0, true
+		return n, nil
+	}
+	copied := copy(b.buf[b.next:], p)
+	if copied < n {
+		copy(b.buf, p[copied:])
+		b.full = true
+	}
+	b.next = (b.next + n) % len(b.buf)
+	if b.next == 0 {
+		b.full = true
+	}
+	return n, nil
+}
+
+// String returns the kept bytes in the order they were written
+func (b *tailBuffer) String() string {
+	if !b.full {
+		return string(b.buf[:b.next])
+	}
+	return string(b.buf[b.next:]) + string(b.buf[:b.next])
+}
@@ -0,0 +1,55 @@
+// pkg/gifmaker/errors_test.go
+package gifmaker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"nothing written", nil, ""},
+		{"under the size", []string{"ab", "cd"}, "abcd"},
+		{"exactly the size", []string{"abc", "de"}, "abcde"},
+		{"wraps around", []string{"abc", "def", "g"}, "cdefg"},
+		{"wraps several times", []string{"abcd", "efgh", "ijkl"}, "hijkl"},
+		{"one write larger than the size", []string{"ab", "cdefghij"}, "fghij"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTailBuffer(5)
+			for _, w := range tt.writes {
+				if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTailBufferKeepsLastError(t *testing.T) {
+	b := newTailBuffer(maxErrorOutput)
+	line := "frame=  100 fps= 25 q=-0.0 size=     512kB time=00:00:04.00\n"
+	for i := 0; i < 2*maxErrorOutput/len(line); i++ {
+		b.Write([]byte(line))
+	}
+	b.Write([]byte("Conversion failed!\n"))
+
+	if got := len(b.String()); got != maxErrorOutput {
+		t.Errorf("kept %d bytes, want %d", got, maxErrorOutput)
+	}
+	lines := ErrorLines(b.String())
+	if len(lines) == 0 || lines[len(lines)-1] != "Conversion failed!" {
+		t.Errorf("ErrorLines() = %v, want it to end with the error", lines)
+	}
+	if !strings.HasSuffix(b.String(), "Conversion failed!\n") {
+		t.Error("the last write was not kept")
+	}
+}
@@ -0,0 +1,679 @@
+// pkg/gifmaker/filters.go
+package gifmaker
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Args assembles the FFmpeg arguments for the conversion
+func (o Options) Args() []string {
+	// -n makes FFmpeg exit instead of prompting when an output exists
+	overwrite := "-y"
+	if o.NoOverwrite {
+		overwrite = "-n"
+	}
+
+	logLevel := o.LogLevel
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	// Add global options for better compatibility
+	ffmpegArgs := []string{
+		overwrite,
+		"-loglevel", logLevel,
+	}
+
+	if o.Threads > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-threads", strconv.Itoa(o.Threads))
+	}
+
+	ffmpegArgs = append(ffmpegArgs,
+		"-progress", "pipe:1",
+		"-stats_period", "0.1",
+	)
+
+	// Hardware decoding has to be requested before the input
+	if o.HWAccel != "" && o.HWAccel != "none" {
+		ffmpegArgs = append(ffmpegArgs, "-hwaccel", o.HWAccel)
+	}
+
+	// Sample and scene modes and speed curves re-time frames with setpts,
+	// and pauses pad extra frames onto the clip, so in all of these the
+	// clip must be trimmed on the input side before filtering.
+	// Fast seek also seeks on the input side, which jumps to the nearest
+	// keyframe instead of decoding everything up to the start time.
+	// Subtitles seek there too so their timing offset is always the same.
+	inputTrim := o.selectsFrames() || o.hasPauses() || len(o.SpeedCurve) > 0
+	inputSeek := inputTrim || o.FastSeek || o.Subtitles != ""
+	if inputSeek && o.Start != "" {
+		ffmpegArgs = append(ffmpegArgs, "-ss", o.Start)
+	}
+	if inputTrim && o.Duration != "" {
+		ffmpegArgs = append(ffmpegArgs, "-t", o.Duration)
+	}
+
+	// Accurate seek splits the output-side seek in two: the input side
+	// jumps to a whole second shortly before the start, and only the rest
+	// is decoded and dropped frame by frame
+	outputStart := o.Start
+	if o.AccurateSeek && !inputSeek && o.Start != "" {
+		if start, err := TimeToSeconds(o.Start); err == nil {
+			if coarse, fine := SplitSeek(start); coarse > 0 {
+				ffmpegArgs = append(ffmpegArgs, "-ss", strconv.FormatFloat(coarse, 'f', -1, 64))
+				outputStart = strconv.FormatFloat(fine, 'f', -1, 64)
+			}
+		}
+	}
+
+	// Ranges are picked from the video's own timestamps, so nothing is
+	// seeked, but decoding can stop after the last one
+	if len(o.Ranges) > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-t", formatExprNumber(o.Ranges[len(o.Ranges)-1].End))
+	}
+
+	ffmpegArgs = append(ffmpegArgs, o.InputArgs()...)
+	ffmpegArgs = append(ffmpegArgs, "-i", o.Input)
+
+	// A precomputed palette is fed in as a second input
+	if o.PaletteFile != "" {
+		ffmpegArgs = append(ffmpegArgs, "-i", o.PaletteFile)
+	}
+
+	if !inputSeek && o.Start != "" {
+		ffmpegArgs = append(ffmpegArgs, "-ss", outputStart)
+	}
+	if !inputTrim && o.Duration != "" {
+		ffmpegArgs = append(ffmpegArgs, "-t", o.Duration)
+	}
+
+	ffmpegArgs = append(ffmpegArgs, "-filter_complex", o.FilterComplex())
+
+	// Each output gets its own copy of the output options
+	for i, output := range o.Outputs() {
+		if len(o.Sizes) > 0 {
+			ffmpegArgs = append(ffmpegArgs, "-map", fmt.Sprintf("[o%d]", i))
+		}
+		if o.SampleFrames > 0 {
+			ffmpegArgs = append(ffmpegArgs, "-frames:v", strconv.Itoa(o.SampleFrames))
+		}
+		ffmpegArgs = append(ffmpegArgs, o.FPSModeArgs()...)
+		if o.Format == FormatWebM {
+			ffmpegArgs = append(ffmpegArgs, "-c:v", "libvpx-vp9", "-b:v", "0", "-crf", strconv.Itoa(QualityToCRF(o.Quality)), "-an")
+			if o.PreserveAlpha {
+				ffmpegArgs = append(ffmpegArgs, "-pix_fmt", "yuva420p")
+			}
+		}
+		if o.Format == FormatMP4 {
+			// yuv420p and faststart let browsers start playing an
+			// autoplaying, looping <video> before it has fully loaded
+			ffmpegArgs = append(ffmpegArgs, "-c:v", "libx264", "-crf", strconv.Itoa(QualityToX264CRF(o.Quality)),
+				"-preset", "slow", "-pix_fmt", "yuv420p", "-movflags", "+faststart", "-an")
+		}
+		if o.StripMetadata {
+			ffmpegArgs = append(ffmpegArgs, "-map_metadata", "-1")
+		}
+		if o.Comment != "" && o.Format != FormatGIF && o.Format != "" {
+			ffmpegArgs = append(ffmpegArgs, "-metadata", "comment="+o.Comment)
+		}
+		ffmpegArgs = append(ffmpegArgs, output)
+	}
+
+	return ffmpegArgs
+}
+
+// SplitSeek splits a start time in seconds into a coarse seek for before
+// the input, a whole number of seconds, and the fine remainder for after
+// it. The coarse part lands at least a second before the start, so a
+// keyframe seek can't overshoot it, and is 0 if the start is within the
+// first second or two. The remainder is rounded to microseconds, FFmpeg's
+// own precision, to drop floating point noise such as 5.3-4 being
+// 1.2999999999999998.
+func SplitSeek(start float64) (coarse, fine float64) {
+	if start <= 0 {
+		return 0, 0
+	}
+	coarse = math.Max(math.Floor(start)-1, 0)
+	fine = math.Round((start-coarse)*1e6) / 1e6
+	return coarse, fine
+}
+
+// InputArgs returns the options that describe how to read the input. They
+// have to come right before its -i.
+func (o Options) InputArgs() []string {
+	var args []string
+	if o.ProtocolWhitelist != "" {
+		args = append(args, "-protocol_whitelist", o.ProtocolWhitelist)
+	}
+	if o.InputFormat != "" {
+		args = append(args, "-f", o.InputFormat)
+	}
+	if o.InputFrameRate != "" {
+		args = append(args, "-framerate", o.InputFrameRate)
+	}
+	if o.NoAutorotate {
+		args = append(args, "-noautorotate")
+	}
+	// FFmpeg's native VP8/VP9 decoders drop the alpha channel
+	if o.Decoder != "" {
+		args = append(args, "-c:v", o.Decoder)
+	}
+	return args
+}
+
+// Outputs returns the files the conversion writes
+func (o Options) Outputs() []string {
+	if len(o.Sizes) == 0 {
+		return []string{o.Output}
+	}
+	outputs := make([]string, len(o.Sizes))
+	for i, w := range o.Sizes {
+		outputs[i] = SizedOutputPath(o.Output, w)
+	}
+	return outputs
+}
+
+// SizedOutputPath inserts the width into an output filename,
+// e.g. clip.gif becomes clip-480.gif
+func SizedOutputPath(output string, width int) string {
+	ext := filepath.Ext(output)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(output, ext), width, ext)
+}
+
+// FilterComplex builds the filter graph used for GIF conversion
+func (o Options) FilterComplex() string {
+	if len(o.Sizes) > 0 {
+		return o.multiSizeFilterComplex()
+	}
+
+	filterComplex := o.BaseFilter()
+
+	// The audio visualization goes on top of the finished frames
+	input := "[0:v]"
+	if o.Waveform != "" {
+		filterComplex = o.waveformGraph(filterComplex)
+		input = ""
+	}
+
+	// VP9 and H.264 handle colors themselves, so video needs no palette
+	if o.Format == FormatWebM || o.Format == FormatMP4 {
+		return filterComplex
+	}
+
+	// Skip palettegen entirely when a palette was supplied
+	if o.PaletteFile != "" {
+		return fmt.Sprintf("%s%s[x];[x][1:v]%s", input, filterComplex, o.PaletteUseFilter())
+	}
+
+	// Add the quality parameter (using palettegen for better quality)
+	filterComplex = fmt.Sprintf("%s,split[s0][s1];[s0]%s[p];[s1][p]%s", filterComplex, o.PaletteGenFilter(), o.PaletteUseFilter())
+
+	return filterComplex
+}
+
+// multiSizeFilterComplex decodes once and splits the stream into one
+// scale and palette branch per requested width, labelled [o0], [o1], ...
+func (o Options) multiSizeFilterComplex() string {
+	n := len(o.Sizes)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[0:v]%s,split=%d", o.FrameFilter(), n)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "[v%d]", i)
+	}
+
+	// A supplied palette has to be split as well since each branch consumes it
+	if o.PaletteFile != "" {
+		fmt.Fprintf(&b, ";[1:v]split=%d", n)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&b, "[p%d]", i)
+		}
+	}
+
+	for i, w := range o.Sizes {
+		fmt.Fprintf(&b, ";[v%d]%s%s", i, o.scaleFilter(w), o.postScaleFilter())
+		if o.evenDimensions() {
+			b.WriteString("," + EvenDimensionsFilter)
+		}
+		if o.Format == FormatWebM || o.Format == FormatMP4 {
+			fmt.Fprintf(&b, "[o%d]", i)
+		} else if o.PaletteFile != "" {
+			fmt.Fprintf(&b, "[s%d];[s%d][p%d]%s[o%d]", i, i, i, o.PaletteUseFilter(), i)
+		} else {
+			fmt.Fprintf(&b, ",split[a%d][b%d];[a%d]%s[p%d];[b%d][p%d]%s[o%d]", i, i, i, o.PaletteGenFilter(), i, i, i, o.PaletteUseFilter(), i)
+		}
+	}
+
+	return b.String()
+}
+
+// BaseFilter builds the frame rate, scaling and clean-up part of the
+// filter chain that runs before palette generation
+func (o Options) BaseFilter() string {
+	filter := o.FrameFilter()
+
+	switch {
+	case o.Width > 0 && o.Height > 0:
+		filter = fmt.Sprintf("%s,%s", filter, FitScaleFilter(o.Width, o.Height, o.Fit))
+	case o.Width > 0:
+		filter = fmt.Sprintf("%s,%s", filter, o.scaleFilter(o.Width))
+	case o.Height > 0:
+		filter = fmt.Sprintf("%s,scale=%d:%d:flags=lanczos", filter, o.autoSide(), o.Height)
+	}
+
+	filter += o.postScaleFilter()
+	// Cropping and padding can leave odd sizes too, so round at the very end
+	if o.evenDimensions() {
+		filter += "," + EvenDimensionsFilter
+	}
+	return filter
+}
+
+// evenDimensions reports whether the output has to have an even width and
+// height
+func (o Options) evenDimensions() bool {
+	return o.EvenDimensions || o.Format == FormatMP4
+}
+
+// autoSide is the scale value for the side that follows the aspect ratio:
+// -2 rounds it to an even number where -1 keeps it exact
+func (o Options) autoSide() int {
+	if o.evenDimensions() {
+		return -2
+	}
+	return -1
+}
+
+// scaleFilter returns the scale filter for the given output width
+func (o Options) scaleFilter(width int) string {
+	return fmt.Sprintf("scale=%d:%d:flags=lanczos", width, o.autoSide())
+}
+
+// Filter parameters for each clean-up level
+var (
+	denoiseParams = map[string]string{
+		LevelLight:  "2:1.5:3:2.25",
+		LevelMedium: "4:3:6:4.5",
+		LevelStrong: "8:6:12:9",
+	}
+	debandParams = map[string]string{
+		LevelLight:  "1thr=0.01:2thr=0.01:3thr=0.01:range=8",
+		LevelMedium: "1thr=0.02:2thr=0.02:3thr=0.02:range=16",
+		LevelStrong: "1thr=0.04:2thr=0.04:3thr=0.04:range=24",
+	}
+)
+
+// TransformFilter returns the filters that rotate the frame clockwise by
+// the given degrees and then mirror it, or "" if there is nothing to do.
+// Rotating by 90 or 270 degrees swaps the width and height.
+func TransformFilter(rotate int, flip string) string {
+	var filters []string
+	switch rotate {
+	case 90:
+		filters = append(filters, "transpose=clock")
+	case 180:
+		filters = append(filters, "hflip", "vflip")
+	case 270:
+		filters = append(filters, "transpose=cclock")
+	}
+	switch flip {
+	case FlipHorizontal:
+		filters = append(filters, "hflip")
+	case FlipVertical:
+		filters = append(filters, "vflip")
+	}
+	return strings.Join(filters, ",")
+}
+
+// subtitlesFilter returns the filters that burn in o.Subtitles, shifted so
+// that they line up with a clip that starts at o.Start
+func (o Options) subtitlesFilter() string {
+	filter := "subtitles=filename=" + EscapeFilterValue(o.Subtitles)
+	if o.SubtitleStyle != "" {
+		filter += ":force_style=" + EscapeFilterValue(o.SubtitleStyle)
+	}
+
+	start, _ := TimeToSeconds(o.Start)
+	if start <= 0 {
+		return filter
+	}
+	offset := strconv.FormatFloat(start, 'f', -1, 64)
+	return fmt.Sprintf("setpts=PTS+%s/TB,%s,setpts=PTS-STARTPTS", offset, filter)
+}
+
+// EscapeFilterValue escapes a value, such as a file path, for use as a
+// filter option inside a filtergraph. Values are unescaped twice: once
+// when the filtergraph is split into filters and once when a filter's
+// options are parsed, so characters special to either level (like the
+// colon in C:\ or the commas in a style list) are escaped for both.
+func EscapeFilterValue(value string) string {
+	return escapeChars(escapeChars(value, `\':`), `\'[],;`)
+}
+
+// escapeChars puts a backslash in front of every character of value that
+// appears in special
+func escapeChars(value, special string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// DedupeFilter returns an mpdecimate filter with FFmpeg's default
+// thresholds (64*12 and 64*5 per 8x8 block) scaled by threshold. Higher
+// values drop more frames.
+func DedupeFilter(threshold float64) string {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return fmt.Sprintf("mpdecimate=hi=%d:lo=%d:frac=0.33", int(math.Round(64*12*threshold)), int(math.Round(64*5*threshold)))
+}
+
+// SharpenFilter returns an unsharp filter for an intensity from 1 to 10.
+// Only luma is sharpened, with the amount growing from 0.15 to 1.5.
+func SharpenFilter(intensity int) string {
+	if intensity > 10 {
+		intensity = 10
+	}
+	return fmt.Sprintf("unsharp=5:5:%s:5:5:0", strconv.FormatFloat(float64(intensity)*0.15, 'f', 2, 64))
+}
+
+// postScaleFilter returns the filters that run on the scaled frames before
+// palette generation, starting with a comma, or "" if there are none.
+// Running them after scaling keeps them cheap on large sources.
+func (o Options) postScaleFilter() string {
+	var filters []string
+	if params, ok := denoiseParams[o.Denoise]; ok {
+		filters = append(filters, "hqdn3d="+params)
+	}
+	if params, ok := debandParams[o.Deband]; ok {
+		filters = append(filters, "deband="+params)
+	}
+	// Sharpen last so it doesn't bring back the noise removed above
+	if o.Sharpen > 0 {
+		filters = append(filters, SharpenFilter(o.Sharpen))
+	}
+	if len(filters) == 0 {
+		return ""
+	}
+	return "," + strings.Join(filters, ",")
+}
+
+// FrameFilter builds the part of the filter chain that decides which
+// frames end up in the GIF and which part of each frame is kept
+func (o Options) FrameFilter() string {
+	// The fps filter duplicates and drops frames to hit a constant rate, so
+	// every GIF frame gets the same delay. It is left out in vfr and
+	// passthrough modes so the source timestamps become the frame delays.
+	// A speed curve has to re-time the frames before they are resampled,
+	// so then the fps filter moves after it.
+	speed := SpeedCurveFilter(o.SpeedCurve)
+	var filters []string
+	if o.usesFPSFilter() && speed == "" {
+		filters = append(filters, o.fpsFilter())
+	}
+
+	// Drop everything outside the ranges early, before the expensive filters
+	if sel := RangeSelectFilter(o.Ranges); sel != "" {
+		filters = append(filters, sel)
+	}
+
+	// Sample mode keeps every Nth frame and shows each for FrameHold seconds
+	hold := strconv.FormatFloat(o.FrameHold, 'f', -1, 64)
+	if o.SampleFrames > 0 {
+		filters = []string{fmt.Sprintf("select='not(mod(n,%d))',setpts=N*%s/TB", o.SampleInterval, hold)}
+	}
+
+	// Scene mode keeps only frames that differ a lot from the previous one
+	if o.SceneThreshold > 0 {
+		filters = []string{fmt.Sprintf("select='gt(scene,%s)',setpts=N*%s/TB", strconv.FormatFloat(o.SceneThreshold, 'f', -1, 64), hold)}
+	}
+
+	// Fix the colors before any filter works with them
+	if color := ColorFilter(o.ColorRange, o.Tonemap); color != "" {
+		filters = append(filters, color)
+	}
+
+	// Rotate before cropping so crop coordinates match what the viewer sees
+	if transform := TransformFilter(o.Rotate, o.Flip); transform != "" {
+		filters = append(filters, transform)
+	}
+
+	if o.Crop != "" {
+		filters = append(filters, "crop="+o.Crop)
+	}
+
+	if o.Pad != "" {
+		filters = append(filters, "pad="+o.Pad+":color=black,setsar=1")
+	}
+
+	// Burn in subtitles on the final framing, before anything re-times the
+	// frames. With the clip seeked on the input side the timestamps start
+	// at zero, so shift them to the video's own time for the subtitles.
+	if o.Subtitles != "" {
+		filters = append(filters, o.subtitlesFilter())
+	}
+
+	// Subtitles are timed against the source, so the ranges are only
+	// joined up after them
+	if join := RangeTimestampFilter(o.Ranges); join != "" {
+		filters = append(filters, join)
+	}
+
+	if speed != "" {
+		filters = append(filters, speed)
+		if o.usesFPSFilter() {
+			filters = append(filters, o.fpsFilter())
+		}
+	}
+
+	// Drop near-duplicate frames, re-timing the rest when the rate is fixed
+	if o.Dedupe {
+		filters = append(filters, DedupeFilter(o.DedupeThreshold))
+		if o.usesFPSFilter() {
+			filters = append(filters, "setpts=N/FRAME_RATE/TB")
+		}
+	}
+
+	// Clone the first and last frames to hold them before the GIF loops
+	if o.hasPauses() {
+		filters = append(filters, fmt.Sprintf("tpad=start_mode=clone:start_duration=%s:stop_mode=clone:stop_duration=%s",
+			strconv.FormatFloat(o.StartPause, 'f', -1, 64), strconv.FormatFloat(o.EndPause, 'f', -1, 64)))
+	}
+
+	// GIF stores frame delays in whole centiseconds, so most frame rates
+	// get rounded (15 fps is 6.67cs, written as 6 or 7). An explicit delay
+	// re-times the selected frames to exactly that many centiseconds apart.
+	if o.Delay > 0 {
+		filters = append(filters, fmt.Sprintf("settb=1/100,setpts=N*%d", o.Delay))
+	}
+
+	// Most filters keep alpha if it's there, but converting explicitly makes
+	// sure palettegen sees it
+	if o.PreserveAlpha {
+		filters = append(filters, "format=rgba")
+	}
+
+	// A filter chain can't be empty
+	if len(filters) == 0 {
+		return "null"
+	}
+
+	return strings.Join(filters, ",")
+}
+
+// fpsFilter returns the filter that brings the frames to a constant FPS,
+// interpolating new frames if asked to instead of repeating them
+func (o Options) fpsFilter() string {
+	return FPSFilter(o.FPS, o.Interpolate)
+}
+
+// FPSFilter returns an fps filter, or a minterpolate filter for an
+// Interpolate* mode. Motion-compensated interpolation uses bidirectional
+// motion estimation with overlapped blocks, which hides most block edges.
+func FPSFilter(fps int, interpolate string) string {
+	switch interpolate {
+	case InterpolateBlend:
+		return fmt.Sprintf("minterpolate=fps=%d:mi_mode=blend", fps)
+	case InterpolateMCI:
+		return fmt.Sprintf("minterpolate=fps=%d:mi_mode=mci:mc_mode=aobmc:me_mode=bidir:vsbmc=1", fps)
+	}
+	return fmt.Sprintf("fps=%d", fps)
+}
+
+// selectsFrames reports whether a select filter picks individual frames
+// instead of converting a continuous clip
+func (o Options) selectsFrames() bool {
+	return o.SampleFrames > 0 || o.SceneThreshold > 0
+}
+
+// hasPauses reports whether the first or last frame is held
+func (o Options) hasPauses() bool {
+	return o.StartPause > 0 || o.EndPause > 0
+}
+
+// usesFPSFilter reports whether frames are resampled to a constant FPS
+func (o Options) usesFPSFilter() bool {
+	return o.FPSMode == "" || o.FPSMode == FPSModeCFR
+}
+
+// FPSModeArgs returns the output options for the frame rate mode. Without
+// the fps filter FFmpeg would otherwise pick its own sync method for GIF.
+func (o Options) FPSModeArgs() []string {
+	if o.usesFPSFilter() || o.selectsFrames() {
+		return nil
+	}
+	return []string{"-fps_mode", o.FPSMode}
+}
+
+// QualityToCRF maps a 1-100 quality to a VP9 CRF between 50 (smallest)
+// and 15 (best)
+func QualityToCRF(quality int) int {
+	if quality < 1 {
+		quality = 1
+	} else if quality > 100 {
+		quality = 100
+	}
+	return 50 - (quality-1)*35/99
+}
+
+// QualityToX264CRF maps a 1-100 quality to an H.264 CRF between 35
+// (smallest) and 18 (visually lossless)
+func QualityToX264CRF(quality int) int {
+	if quality < 1 {
+		quality = 1
+	} else if quality > 100 {
+		quality = 100
+	}
+	return 35 - (quality-1)*17/99
+}
+
+// EvenDimensionsFilter rounds the frame size down to even numbers, which
+// yuv420p H.264 requires. It leaves frames that are already even alone.
+const EvenDimensionsFilter = "scale=trunc(iw/2)*2:trunc(ih/2)*2"
+
+// ScaleFilter returns the scale filter for the given output width
+func ScaleFilter(width int) string {
+	return fmt.Sprintf("scale=%d:-1:flags=lanczos", width)
+}
+
+// FitScaleFilter returns the filter that scales the frame into a
+// width x height box the way fit (a Fit* constant) asks. Contain may leave
+// the frame smaller than the box along one side.
+func FitScaleFilter(width, height int, fit string) string {
+	switch fit {
+	case FitStretch:
+		return fmt.Sprintf("scale=%d:%d:flags=lanczos", width, height)
+	case FitCover:
+		return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase:flags=lanczos,crop=%d:%d", width, height, width, height)
+	default:
+		return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:flags=lanczos", width, height)
+	}
+}
+
+// AlphaThreshold is the alpha value below which a pixel becomes transparent
+const AlphaThreshold = 128
+
+// qualityLevels maps quality ranges to a palette size and dithering mode,
+// from the best down. Fewer colors and ordered (bayer) dithering compress
+// much better than error diffusion, at the cost of visible banding.
+var qualityLevels = []struct {
+	minQuality int
+	colors     int
+	dither     string
+}{
+	{90, 256, "sierra2_4a"},
+	{75, 192, "bayer:bayer_scale=2"},
+	{50, 128, "bayer:bayer_scale=3"},
+	{25, 64, "bayer:bayer_scale=4"},
+	{0, 32, "none"},
+}
+
+// QualityPalette returns the palette size and paletteuse dither mode for a
+// 1-100 quality. A quality of 0 (unset) gets the best palette.
+func QualityPalette(quality int) (colors int, dither string) {
+	if quality <= 0 {
+		quality = 100
+	}
+	for _, level := range qualityLevels {
+		if quality >= level.minQuality {
+			return level.colors, level.dither
+		}
+	}
+	last := qualityLevels[len(qualityLevels)-1]
+	return last.colors, last.dither
+}
+
+// BayerDither returns the paletteuse dither mode for ordered dithering with
+// the given bayer_scale, from 0 (strongest pattern, least banding) to 5
+func BayerDither(scale int) string {
+	return fmt.Sprintf("bayer:bayer_scale=%d", scale)
+}
+
+// PaletteGenFilter returns the palettegen filter
+func (o Options) PaletteGenFilter() string {
+	colors, _ := QualityPalette(o.Quality)
+	if o.MaxColors > 0 {
+		colors = o.MaxColors
+	}
+	statsMode := o.StatsMode
+	if statsMode == "" {
+		statsMode = StatsModeDiff
+	}
+	filter := fmt.Sprintf("palettegen=max_colors=%d:stats_mode=%s", colors, statsMode)
+	// Keep a palette slot free for the transparent color
+	if o.PreserveAlpha {
+		filter += ":reserve_transparent=1"
+	}
+	return filter
+}
+
+// PaletteUseFilter returns the paletteuse filter
+func (o Options) PaletteUseFilter() string {
+	_, dither := QualityPalette(o.Quality)
+	if o.Dither != "" {
+		dither = o.Dither
+	}
+	diffMode := o.DiffMode
+	if diffMode == "" {
+		diffMode = DiffModeRectangle
+	}
+	alphaThreshold := AlphaThreshold
+	if o.AlphaCutoff > 0 {
+		alphaThreshold = o.AlphaCutoff
+	}
+	filter := fmt.Sprintf("paletteuse=dither=%s:diff_mode=%s:alpha_threshold=%d", dither, diffMode, alphaThreshold)
+	// Per-frame palettes have to be picked up as they arrive
+	if o.StatsMode == StatsModeSingle {
+		filter += ":new=1"
+	}
+	return filter
+}
@@ -0,0 +1,181 @@
+// pkg/gifmaker/filters_test.go
+package gifmaker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTransformFilter(t *testing.T) {
+	tests := []struct {
+		rotate int
+		flip   string
+		want   string
+	}{
+		{0, "", ""},
+		{0, FlipHorizontal, "hflip"},
+		{0, FlipVertical, "vflip"},
+		{90, "", "transpose=clock"},
+		{90, FlipHorizontal, "transpose=clock,hflip"},
+		{90, FlipVertical, "transpose=clock,vflip"},
+		{180, "", "hflip,vflip"},
+		{180, FlipHorizontal, "hflip,vflip,hflip"},
+		{180, FlipVertical, "hflip,vflip,vflip"},
+		{270, "", "transpose=cclock"},
+		{270, FlipHorizontal, "transpose=cclock,hflip"},
+		{270, FlipVertical, "transpose=cclock,vflip"},
+	}
+	for _, tt := range tests {
+		if got := TransformFilter(tt.rotate, tt.flip); got != tt.want {
+			t.Errorf("TransformFilter(%d, %q) = %q, want %q", tt.rotate, tt.flip, got, tt.want)
+		}
+	}
+}
+
+func TestQualityPaletteFilters(t *testing.T) {
+	tests := []struct {
+		quality int
+		gen     string
+		use     string
+	}{
+		{100, "palettegen=max_colors=256:stats_mode=diff", "paletteuse=dither=sierra2_4a:diff_mode=rectangle:alpha_threshold=128"},
+		{90, "palettegen=max_colors=256:stats_mode=diff", "paletteuse=dither=sierra2_4a:diff_mode=rectangle:alpha_threshold=128"},
+		{80, "palettegen=max_colors=192:stats_mode=diff", "paletteuse=dither=bayer:bayer_scale=2:diff_mode=rectangle:alpha_threshold=128"},
+		{60, "palettegen=max_colors=128:stats_mode=diff", "paletteuse=dither=bayer:bayer_scale=3:diff_mode=rectangle:alpha_threshold=128"},
+		{30, "palettegen=max_colors=64:stats_mode=diff", "paletteuse=dither=bayer:bayer_scale=4:diff_mode=rectangle:alpha_threshold=128"},
+		{10, "palettegen=max_colors=32:stats_mode=diff", "paletteuse=dither=none:diff_mode=rectangle:alpha_threshold=128"},
+		{0, "palettegen=max_colors=256:stats_mode=diff", "paletteuse=dither=sierra2_4a:diff_mode=rectangle:alpha_threshold=128"},
+	}
+	for _, tt := range tests {
+		opts := Options{Quality: tt.quality}
+		if got := opts.PaletteGenFilter(); got != tt.gen {
+			t.Errorf("quality %d: PaletteGenFilter() = %q, want %q", tt.quality, got, tt.gen)
+		}
+		if got := opts.PaletteUseFilter(); got != tt.use {
+			t.Errorf("quality %d: PaletteUseFilter() = %q, want %q", tt.quality, got, tt.use)
+		}
+	}
+
+	// Every quality level has to change the output, or it isn't a level
+	seen := map[string]int{}
+	for _, quality := range []int{95, 80, 60, 30, 10} {
+		opts := Options{Quality: quality}
+		key := opts.PaletteGenFilter() + ";" + opts.PaletteUseFilter()
+		if prev, ok := seen[key]; ok {
+			t.Errorf("qualities %d and %d give the same palette filters %q", prev, quality, key)
+		}
+		seen[key] = quality
+	}
+}
+
+func TestFitScaleFilter(t *testing.T) {
+	tests := []struct {
+		width, height int
+		fit           string
+		want          string
+	}{
+		{480, 270, FitContain, "scale=480:270:force_original_aspect_ratio=decrease:flags=lanczos"},
+		{480, 270, "", "scale=480:270:force_original_aspect_ratio=decrease:flags=lanczos"},
+		{480, 480, FitCover, "scale=480:480:force_original_aspect_ratio=increase:flags=lanczos,crop=480:480"},
+		{480, 270, FitStretch, "scale=480:270:flags=lanczos"},
+		{321, 241, FitContain, "scale=321:241:force_original_aspect_ratio=decrease:flags=lanczos"},
+		{321, 241, FitCover, "scale=321:241:force_original_aspect_ratio=increase:flags=lanczos,crop=321:241"},
+		{321, 241, FitStretch, "scale=321:241:flags=lanczos"},
+	}
+	for _, tt := range tests {
+		if got := FitScaleFilter(tt.width, tt.height, tt.fit); got != tt.want {
+			t.Errorf("FitScaleFilter(%d, %d, %q) = %q, want %q", tt.width, tt.height, tt.fit, got, tt.want)
+		}
+	}
+}
+
+func TestBaseFilterFitEvenDimensions(t *testing.T) {
+	// Contain can leave an odd side inside an odd box, so rounding to even
+	// has to come after the fit, whatever the mode
+	for _, fit := range []string{FitContain, FitCover, FitStretch} {
+		t.Run(fit, func(t *testing.T) {
+			opts := Options{FPS: 10, Width: 321, Height: 241, Fit: fit, EvenDimensions: true}
+			filter := opts.BaseFilter()
+			scale := FitScaleFilter(321, 241, fit)
+			if !strings.Contains(filter, scale) {
+				t.Errorf("BaseFilter() = %q, missing %q", filter, scale)
+			}
+			if !strings.HasSuffix(filter, ","+EvenDimensionsFilter) {
+				t.Errorf("BaseFilter() = %q, want it to end with %q", filter, EvenDimensionsFilter)
+			}
+			if strings.Index(filter, scale) > strings.LastIndex(filter, EvenDimensionsFilter) {
+				t.Errorf("BaseFilter() = %q rounds to even before fitting", filter)
+			}
+
+			opts.EvenDimensions = false
+			if filter := opts.BaseFilter(); strings.Contains(filter, EvenDimensionsFilter) {
+				t.Errorf("BaseFilter() without EvenDimensions = %q, want no rounding", filter)
+			}
+		})
+	}
+}
+
+// seeks returns the -ss values given before and after the first -i
+func seeks(args []string) (input, output []string) {
+	seenInput := false
+	for i := 0; i < len(args)-1; i++ {
+		switch args[i] {
+		case "-i":
+			seenInput = true
+		case "-ss":
+			if seenInput {
+				output = append(output, args[i+1])
+			} else {
+				input = append(input, args[i+1])
+			}
+		}
+	}
+	return input, output
+}
+
+func TestSplitSeek(t *testing.T) {
+	tests := []struct {
+		name         string
+		start        float64
+		coarse, fine float64
+	}{
+		{"start 0", 0, 0, 0},
+		{"below the margin", 1.5, 0, 0},
+		{"just below the margin", 1.999, 0, 0},
+		{"exactly the margin", 2, 1, 1},
+		{"fraction above the margin", 5.3, 4, 1.3},
+		{"large start", 3723.25, 3722, 1.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coarse, fine := SplitSeek(tt.start)
+			// Only the coarse part matters when there is nothing to split
+			if coarse != tt.coarse || (coarse > 0 && fine != tt.fine) {
+				t.Errorf("SplitSeek(%g) = %g, %g, want %g, %g", tt.start, coarse, fine, tt.coarse, tt.fine)
+			}
+		})
+	}
+}
+
+func TestArgsAccurateSeek(t *testing.T) {
+	tests := []struct {
+		name          string
+		start         string
+		input, output []string
+	}{
+		{"start 0", "0", nil, []string{"0"}},
+		{"below the margin", "1.5", nil, []string{"1.5"}},
+		{"exactly the margin", "2", []string{"1"}, []string{"1"}},
+		{"large start", "01:02:03.25", []string{"3722"}, []string{"1.25"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Input: "in.mp4", Output: "out.gif", FPS: 10, Quality: 90, Start: tt.start, AccurateSeek: true}
+			input, output := seeks(opts.Args())
+			if !slices.Equal(input, tt.input) || !slices.Equal(output, tt.output) {
+				t.Errorf("Args() seeks %v before the input and %v after, want %v and %v", input, output, tt.input, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// Package gifmaker converts videos to GIFs with FFmpeg. It is the engine
+// behind the gif-maker CLI and can be used on its own.
+package gifmaker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Akashdeep-Patra/gif-maker/internal/ffmpeg"
+)
+
+// Supported output formats
+const (
+	FormatGIF  = "gif"
+	FormatWebM = "webm"
+	FormatMP4  = "mp4" // H.264 for web embedding, the "gifv" style
+)
+
+// Frame rate modes. GIF frame delays come from frame timestamps, so the
+// mode decides whether every frame gets the same delay (cfr) or keeps the
+// source's own timing (vfr, passthrough).
+const (
+	FPSModeCFR         = "cfr"         // Resample to FPS with the fps filter
+	FPSModeVFR         = "vfr"         // Keep source timing, drop frames with duplicate timestamps
+	FPSModePassthrough = "passthrough" // Keep every source frame and its timestamp
+)
+
+// Frame interpolation modes for minterpolate, which makes up the frames a
+// low frame rate source lacks instead of repeating them
+const (
+	InterpolateBlend = "blend" // Cross-fade neighboring frames; fast, ghosts on fast motion
+	InterpolateMCI   = "mci"   // Motion-compensated; smooth, but very slow and can warp edges
+)
+
+// Palette statistics modes for palettegen
+const (
+	StatsModeDiff   = "diff"   // One palette favoring the parts that move
+	StatsModeFull   = "full"   // One palette for every pixel of every frame
+	StatsModeSingle = "single" // A new palette for every frame
+)
+
+// paletteuse diff modes, which decide how much of each frame is re-dithered
+const (
+	DiffModeRectangle = "rectangle" // Only the rectangle that changed, so static areas don't shimmer
+	DiffModeNone      = "none"      // Every pixel of every frame
+)
+
+// How the frame fits a box given by both Width and Height
+const (
+	FitContain = "contain" // Fit inside the box, keeping the aspect ratio
+	FitCover   = "cover"   // Fill the box, keeping the aspect ratio, and crop the overflow
+	FitStretch = "stretch" // Scale to exactly the box, distorting if needed
+)
+
+// Flip directions
+const (
+	FlipHorizontal = "h"
+	FlipVertical   = "v"
+)
+
+// Strength levels for the clean-up filters
+const (
+	LevelLight  = "light"
+	LevelMedium = "medium"
+	LevelStrong = "strong"
+)
+
+// Options describes a single conversion
+type Options struct {
+	FFmpegPath  string // FFmpeg binary to run; the embedded one is used if empty
+	Input       string
+	Output      string
+	FPS         int
+	FPSMode     string // FPSModeCFR (default), FPSModeVFR or FPSModePassthrough
+	Interpolate string // InterpolateBlend or InterpolateMCI to synthesize frames up to FPS; cfr only
+	Delay       int    // GIF frame delay in centiseconds, overrides the FPS-derived delay
+	Start       string // Start time (format: 00:00:00)
+	Duration    string // Clip length (format: 00:00:00)
+	FastSeek    bool   // Seek before -i: faster, but only keyframe accurate
+	Width       int    // Output width in pixels, 0 keeps the input width
+	Height      int    // Output height in pixels, 0 keeps the aspect ratio
+	Fit         string // FitContain (default), FitCover or FitStretch when both Width and Height are set
+	Quality     int    // 1-100; sets the GIF palette size and dithering, or the CRF for WebM and MP4
+	Format      string // FormatGIF (default), FormatWebM or FormatMP4
+	Threads     int    // FFmpeg threads, 0 lets FFmpeg decide
+	Nice        int    // Niceness to run FFmpeg at (Unix only), 0 keeps the default
+	HWAccel     string
+
+	// EvenDimensions keeps the output width and height even, which H.264
+	// and some filters need. It is always on for FormatMP4.
+	EvenDimensions bool
+
+	// AccurateSeek seeks to Start in two steps, see SplitSeek: quickly on
+	// the input side to shortly before it, then exactly on the output
+	// side. Ignored with FastSeek or when the input side has to seek anyway.
+	AccurateSeek bool
+
+	NoOverwrite bool // Fail instead of replacing existing output files
+
+	// StripMetadata drops the source's metadata (titles, dates, GPS tags)
+	// from the output. Comment is stored as metadata in WebM and MP4;
+	// FFmpeg can't write it to GIFs, so for those use AddGIFComment on
+	// the finished file.
+	StripMetadata bool
+	Comment       string
+
+	// LogLevel is FFmpeg's -loglevel, "info" if empty. If Stderr is set,
+	// FFmpeg's log output is copied to it as it is written.
+	LogLevel string
+	Stderr   io.Writer
+
+	PaletteFile string // Precomputed palette PNG, skips palette generation
+	StatsMode   string // StatsModeDiff (default), StatsModeFull or StatsModeSingle
+	MaxColors   int    // Palette size (2-256), overriding the one from Quality
+
+	// Fine-tuning of paletteuse; empty or 0 keeps the defaults. Dither
+	// replaces the dithering picked from Quality, e.g. BayerDither(1), and
+	// AlphaCutoff (1-255) the AlphaThreshold used with PreserveAlpha.
+	Dither      string
+	DiffMode    string // DiffModeRectangle (default) or DiffModeNone
+	AlphaCutoff int
+
+	// Sample mode keeps SampleFrames frames, one every SampleInterval
+	// source frames, and shows each for FrameHold seconds
+	SampleFrames   int
+	SampleInterval int
+	FrameHold      float64
+
+	// SceneThreshold keeps only frames whose scene change score (0-1) is
+	// above it, each shown for FrameHold seconds. 0 disables it.
+	SceneThreshold float64
+
+	// Hold the first and last frame for this many seconds
+	StartPause float64
+	EndPause   float64
+
+	// Subtitles burns in captions from an SRT, ASS or other subtitle file
+	// readable by libass. Timing follows the source video, so captions line
+	// up with a clip cut by Start. SubtitleStyle overrides ASS style fields,
+	// e.g. "FontSize=24,PrimaryColour=&H00FFFF&".
+	Subtitles     string
+	SubtitleStyle string
+
+	// Dedupe drops frames that barely differ from the previous one, which
+	// shrinks GIFs of mostly static content such as screen recordings. In
+	// cfr mode the kept frames are re-timed to play back to back, so static
+	// stretches are skipped; otherwise the source timestamps are kept.
+	Dedupe          bool
+	DedupeThreshold float64 // Scales mpdecimate's thresholds; 0 or 1 uses FFmpeg's defaults
+
+	// SpeedCurve changes the playback speed over the clip, interpolating
+	// linearly between keyframes. A single keyframe is a constant speed.
+	SpeedCurve []SpeedKeyframe
+
+	// Ranges joins several parts of the video into one clip, in the order
+	// they appear. They replace Start and Duration, which must be empty.
+	Ranges []TimeRange
+
+	Sizes  []int  // Write one GIF per width instead of a single Output
+	Rotate int    // Clockwise rotation in degrees: 0, 90, 180 or 270
+	Flip   string // Mirror the frame: FlipHorizontal or FlipVertical
+	Crop   string // Crop as W:H:X:Y, applied after rotating and before scaling
+	Pad    string // Pad to a W:H canvas with the frame at X:Y, applied after cropping
+
+	// ColorRange overrides the range the source is read as (a ColorRange*
+	// constant) and Tonemap converts HDR to SDR with a Tonemap* operator.
+	// Empty leaves FFmpeg's defaults.
+	ColorRange string
+	Tonemap    string
+
+	// Clean-up filters for noisy or low-bitrate sources, run before palette
+	// generation so the palette isn't spent on noise. Each is a Level*
+	// constant, or empty to turn it off.
+	Denoise string
+	Deband  string
+	Sharpen int // 0-10, sharpens the scaled frames; 0 turns it off
+
+	// Waveform overlays a visualization of the input's audio on the frames,
+	// WaveformWaves or WaveformSpectrum; the input must have an audio
+	// stream. The band spans the frame width and WaveformHeight percent
+	// (DefaultWaveformHeight if 0) of its height, at WaveformPosition
+	// (WaveformBottom if empty). WaveformColor is the color of the waves,
+	// an FFmpeg color such as "white" or "0x00ff00@0.8". Not supported with
+	// Sizes.
+	Waveform         string
+	WaveformPosition string
+	WaveformHeight   int
+	WaveformColor    string
+
+	// PreserveAlpha keeps transparency from sources with an alpha channel.
+	// GIF transparency is binary: pixels below AlphaThreshold become fully
+	// transparent and everything else fully opaque.
+	PreserveAlpha bool
+	Decoder       string // Input decoder to force, e.g. libvpx-vp9 to read WebM alpha
+
+	// Raw streams such as .h264 or .yuv need the demuxer (and for some,
+	// the frame rate) spelled out because FFmpeg can't detect them
+	InputFormat    string // Demuxer name passed as -f, e.g. h264 or rawvideo
+	InputFrameRate string // Input frame rate, e.g. 30 or 30000/1001
+
+	// NoAutorotate stops FFmpeg from applying the source's rotation
+	// metadata, for callers that apply it explicitly through Rotate
+	NoAutorotate bool
+
+	// ProtocolWhitelist limits the protocols FFmpeg may open while reading
+	// Input, e.g. "file,pipe" for untrusted files whose playlists could
+	// otherwise make it fetch URLs. Empty keeps FFmpeg's default.
+	ProtocolWhitelist string
+}
+
+// Result describes a finished conversion
+type Result struct {
+	Outputs        []string
+	Frames         int
+	AvgProcessRate float64 // Average processing rate relative to real-time
+	Elapsed        time.Duration
+}
+
+// Convert runs the conversion described by opts. If onProgress is not nil it
+// is called with the latest state every time FFmpeg reports progress.
+// Cancelling ctx kills FFmpeg and removes any partially written output.
+func Convert(ctx context.Context, opts Options, onProgress func(Progress)) (Result, error) {
+	result := Result{Outputs: opts.Outputs()}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	ffmpegPath := opts.FFmpegPath
+	if ffmpegPath == "" {
+		manager := ffmpeg.NewManager()
+		defer manager.Cleanup()
+
+		path, err := manager.GetPath()
+		if err != nil {
+			return result, fmt.Errorf("Failed to get FFmpeg: %w", err)
+		}
+		ffmpegPath = path
+	}
+
+	startTime := time.Now()
+	ffmpegCmd := exec.CommandContext(ctx, ffmpegPath, opts.Args()...)
+
+	stdout, err := ffmpegCmd.StdoutPipe()
+	if err != nil {
+		return result, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+
+	// Keep the end of stderr around for the error message
+	errOutput := newTailBuffer(maxErrorOutput)
+	ffmpegCmd.Stderr = errOutput
+	if opts.Stderr != nil {
+		ffmpegCmd.Stderr = io.MultiWriter(errOutput, opts.Stderr)
+	}
+
+	if err := ffmpegCmd.Start(); err != nil {
+		return result, fmt.Errorf("failed to start FFmpeg: %w", err)
+	}
+
+	// A failed renice only makes FFmpeg compete harder for the CPU, so the
+	// conversion carries on regardless
+	if opts.Nice != 0 {
+		_ = SetNice(ffmpegCmd.Process, opts.Nice)
+	}
+
+	// Track average processing rate
+	var speedSum float64
+	var speedCount int
+
+	// Create a scanner with a larger buffer for FFmpeg output
+	scanner := bufio.NewScanner(stdout)
+	buf := make([]byte, 64*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	var progress Progress
+	for scanner.Scan() {
+		prev := progress
+		if !ParseProgressLine(scanner.Text(), &progress) {
+			continue
+		}
+
+		if progress.ProcessingRate != prev.ProcessingRate && progress.ProcessingRate > 0 {
+			speedSum += progress.ProcessingRate
+			speedCount++
+		}
+
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+
+	// Wait for the command to finish
+	if err := ffmpegCmd.Wait(); err != nil {
+		// A killed FFmpeg leaves a truncated GIF behind
+		if ctx.Err() != nil {
+			removeOutputs(result.Outputs)
+			return result, fmt.Errorf("conversion cancelled: %w", ctx.Err())
+		}
+
+		output := errOutput.String()
+		return result, &FFmpegError{
+			Err:    err,
+			Lines:  ErrorLines(output),
+			Output: output,
+		}
+	}
+
+	result.Frames = int(progress.FramesProcessed)
+	if speedCount > 0 {
+		result.AvgProcessRate = speedSum / float64(speedCount)
+	}
+	result.Elapsed = time.Since(startTime)
+
+	return result, nil
+}
+
+// removeOutputs deletes the given files, ignoring any that don't exist
+func removeOutputs(outputs []string) {
+	for _, output := range outputs {
+		os.Remove(output)
+	}
+}
@@ -0,0 +1,55 @@
+// pkg/gifmaker/gifmaker_test.go
+package gifmaker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// A stand-in for FFmpeg that starts writing the output, reports some
+// progress and then hangs until it is killed
+const hangingFFmpeg = `#!/bin/sh
+for last; do :; done
+printf 'GIF89a' > "$last"
+echo "out_time_us=1000000"
+echo "progress=continue"
+exec sleep 30
+`
+
+func TestConvertCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for FFmpeg")
+	}
+
+	dir := t.TempDir()
+	ffmpegPath := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(ffmpegPath, []byte(hangingFFmpeg), 0755); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out.gif")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := Options{
+		Input:      filepath.Join(dir, "in.mp4"),
+		Output:     output,
+		FPS:        10,
+		Quality:    90,
+		FFmpegPath: ffmpegPath,
+	}
+	// Cancel once FFmpeg is busy, as Ctrl+C would
+	_, err := Convert(ctx, opts, func(Progress) { cancel() })
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Convert error = %v, want one wrapping context.Canceled", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("partial output %s was left behind (stat error %v)", output, err)
+	}
+}
@@ -0,0 +1,15 @@
+// pkg/gifmaker/priority_other.go
+
+//go:build !unix
+
+package gifmaker
+
+import (
+	"errors"
+	"os"
+)
+
+// SetNice is only supported on Unix
+func SetNice(p *os.Process, nice int) error {
+	return errors.New("process priority can only be changed on Unix")
+}
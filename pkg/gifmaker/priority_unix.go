@@ -0,0 +1,17 @@
+// pkg/gifmaker/priority_unix.go
+
+//go:build unix
+
+package gifmaker
+
+import (
+	"os"
+	"syscall"
+)
+
+// SetNice sets the scheduling niceness of a running process, from -20
+// (highest priority) to 19 (lowest). Raising the priority above the
+// default needs root.
+func SetNice(p *os.Process, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, p.Pid, nice)
+}
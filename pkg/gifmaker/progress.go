@@ -0,0 +1,229 @@
+// pkg/gifmaker/progress.go
+package gifmaker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Progress is a snapshot of a running conversion as reported by FFmpeg
+type Progress struct {
+	CurrentTime     float64
+	TotalDuration   float64
+	ProcessingRate  float64 // Speed relative to real-time playback
+	CurrentSize     int64
+	SizeUnit        string
+	Bitrate         float64
+	BitrateUnit     string
+	FramesProcessed int64
+	Width           int
+	Height          int
+	Done            bool // Set once FFmpeg reports progress=end
+}
+
+// Patterns for FFmpeg's human-readable stderr output
+var (
+	durationLineRegex = regexp.MustCompile(`Duration: (\d{2}:\d{2}:\d{2}\.\d{2})`)
+	videoStreamRegex  = regexp.MustCompile(`Stream #.*Video:.* (\d+)x(\d+)`)
+	statsTimeRegex    = regexp.MustCompile(`time=(\d{2}:\d{2}:\d{2}\.\d{2})`)
+	statsSpeedRegex   = regexp.MustCompile(`speed=\s*(\d+(?:\.\d+)?)x`)
+	statsSizeRegex    = regexp.MustCompile(`size=\s*(\d+)\s*([kKmMgG]i?B|[kKmMgG]|B)`)
+	statsFrameRegex   = regexp.MustCompile(`frame=\s*(\d+)`)
+	statsBitrateRegex = regexp.MustCompile(`bitrate=\s*(\d+(?:\.\d+)?)(\S+)`)
+)
+
+// Keys from the -progress stream that parseProgressKeyValue understands
+var progressKeys = map[string]bool{
+	"out_time_us": true,
+	"out_time_ms": true,
+	"speed":       true,
+	"total_size":  true,
+	"frame":       true,
+	"duration":    true,
+	"progress":    true,
+}
+
+// ParseProgressLine parses a single line of FFmpeg output into update and
+// reports whether any field changed. It understands both the key=value lines
+// written by -progress and the human-readable lines written to stderr.
+func ParseProgressLine(line string, update *Progress) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	// Structured -progress output has exactly one key=value pair per line
+	if key, value, ok := strings.Cut(line, "="); ok && progressKeys[key] && !strings.Contains(value, "=") {
+		return parseProgressKeyValue(key, strings.TrimSpace(value), update)
+	}
+
+	changed := false
+
+	if matches := durationLineRegex.FindStringSubmatch(line); matches != nil {
+		if d, err := TimeToSeconds(matches[1]); err == nil && d > 0 && d != update.TotalDuration {
+			update.TotalDuration = d
+			changed = true
+		}
+	}
+
+	if matches := videoStreamRegex.FindStringSubmatch(line); matches != nil {
+		w, err1 := strconv.Atoi(matches[1])
+		h, err2 := strconv.Atoi(matches[2])
+		if err1 == nil && err2 == nil && w > 0 && h > 0 && (w != update.Width || h != update.Height) {
+			update.Width = w
+			update.Height = h
+			changed = true
+		}
+	}
+
+	if matches := statsTimeRegex.FindStringSubmatch(line); matches != nil {
+		if t, err := TimeToSeconds(matches[1]); err == nil && t > 0 && t != update.CurrentTime {
+			update.CurrentTime = t
+			changed = true
+		}
+	}
+
+	if matches := statsSpeedRegex.FindStringSubmatch(line); matches != nil {
+		if s, err := strconv.ParseFloat(matches[1], 64); err == nil && s > 0 && s != update.ProcessingRate {
+			update.ProcessingRate = s
+			changed = true
+		}
+	}
+
+	if matches := statsSizeRegex.FindStringSubmatch(line); matches != nil {
+		if s, err := strconv.ParseInt(matches[1], 10, 64); err == nil && s > 0 && (s != update.CurrentSize || matches[2] != update.SizeUnit) {
+			update.CurrentSize = s
+			update.SizeUnit = matches[2]
+			changed = true
+		}
+	}
+
+	if matches := statsFrameRegex.FindStringSubmatch(line); matches != nil {
+		if f, err := strconv.ParseInt(matches[1], 10, 64); err == nil && f > 0 && f != update.FramesProcessed {
+			update.FramesProcessed = f
+			changed = true
+		}
+	}
+
+	if matches := statsBitrateRegex.FindStringSubmatch(line); matches != nil {
+		if b, err := strconv.ParseFloat(matches[1], 64); err == nil && (b != update.Bitrate || matches[2] != update.BitrateUnit) {
+			update.Bitrate = b
+			update.BitrateUnit = matches[2]
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// parseProgressKeyValue applies a single -progress key=value pair to update
+func parseProgressKeyValue(key, value string, update *Progress) bool {
+	switch key {
+	case "out_time_us", "out_time_ms":
+		// Despite its name, out_time_ms is also reported in microseconds
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && us > 0 {
+			t := float64(us) / 1000000.0
+			if t != update.CurrentTime {
+				update.CurrentTime = t
+				return true
+			}
+		}
+
+	case "speed":
+		s, err := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+		if err == nil && s > 0 && s != update.ProcessingRate {
+			update.ProcessingRate = s
+			return true
+		}
+
+	case "total_size":
+		s, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && s > 0 && (s != update.CurrentSize || update.SizeUnit != "b") {
+			update.CurrentSize = s
+			update.SizeUnit = "b"
+			return true
+		}
+
+	case "frame":
+		f, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && f > 0 && f != update.FramesProcessed {
+			update.FramesProcessed = f
+			return true
+		}
+
+	case "duration":
+		d, err := strconv.ParseFloat(value, 64)
+		if err == nil && d > 0 && d != update.TotalDuration {
+			update.TotalDuration = d
+			return true
+		}
+
+	case "progress":
+		if value == "end" && !update.Done {
+			update.Done = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// Patterns for the parts of a time string
+var (
+	timeWholeRegex   = regexp.MustCompile(`^\d+$`)
+	timeSecondsRegex = regexp.MustCompile(`^\d+(\.\d+)?$`)
+)
+
+// TimeToSeconds converts a time string to seconds. It accepts HH:MM:SS,
+// MM:SS and bare seconds, each optionally with a fractional part on the
+// seconds (e.g. 01:02:03.5, 02:03, 90). An empty string is 0 seconds.
+func TimeToSeconds(timeStr string) (float64, error) {
+	timeStr = strings.TrimSpace(timeStr)
+	if timeStr == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(timeStr, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q: too many components", timeStr)
+	}
+
+	last := parts[len(parts)-1]
+	if !timeSecondsRegex.MatchString(last) {
+		return 0, fmt.Errorf("invalid time %q: bad seconds %q", timeStr, last)
+	}
+	seconds, err := strconv.ParseFloat(last, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", timeStr, err)
+	}
+
+	// Minutes and hours are whole numbers, most significant first
+	var total float64
+	for _, part := range parts[:len(parts)-1] {
+		if !timeWholeRegex.MatchString(part) {
+			return 0, fmt.Errorf("invalid time %q: bad component %q", timeStr, part)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q: %w", timeStr, err)
+		}
+		total = total*60 + float64(n)
+	}
+
+	// Seconds and minutes only roll over when there is a larger unit
+	if len(parts) > 1 {
+		if seconds >= 60 {
+			return 0, fmt.Errorf("invalid time %q: seconds must be less than 60", timeStr)
+		}
+		if len(parts) == 3 {
+			if minutes, _ := strconv.Atoi(parts[1]); minutes >= 60 {
+				return 0, fmt.Errorf("invalid time %q: minutes must be less than 60", timeStr)
+			}
+		}
+	}
+
+	return total*60 + seconds, nil
+}
@@ -0,0 +1,176 @@
+// pkg/gifmaker/progress_test.go
+package gifmaker
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		start   Progress
+		want    Progress
+		changed bool
+	}{
+		{"out_time_us", "out_time_us=1500000", Progress{}, Progress{CurrentTime: 1.5}, true},
+		{"out_time_ms is microseconds too", "out_time_ms=2500000", Progress{}, Progress{CurrentTime: 2.5}, true},
+		{"same time", "out_time_us=1500000", Progress{CurrentTime: 1.5}, Progress{CurrentTime: 1.5}, false},
+		{"negative time before the first frame", "out_time_us=-9223372036854775807", Progress{}, Progress{}, false},
+		{"speed", "speed=1.25x", Progress{}, Progress{ProcessingRate: 1.25}, true},
+		{"speed N/A", "speed=N/A", Progress{ProcessingRate: 2}, Progress{ProcessingRate: 2}, false},
+		{"total_size", "total_size=4096", Progress{}, Progress{CurrentSize: 4096, SizeUnit: "b"}, true},
+		{"total_size N/A", "total_size=N/A", Progress{}, Progress{}, false},
+		{"frame", "frame=42", Progress{}, Progress{FramesProcessed: 42}, true},
+		{"progress end", "progress=end", Progress{}, Progress{Done: true}, true},
+		{"progress continue", "progress=continue", Progress{}, Progress{}, false},
+		{"padded value", "  frame=  7  ", Progress{}, Progress{FramesProcessed: 7}, true},
+		{"empty", "", Progress{}, Progress{}, false},
+		{"unknown key", "bitrate_kbps=12", Progress{}, Progress{}, false},
+		{"no value", "frame=", Progress{}, Progress{}, false},
+		{"not a number", "frame=abc", Progress{}, Progress{}, false},
+		{"no equals sign", "garbage", Progress{}, Progress{}, false},
+		{
+			"stderr stats line",
+			"frame=  120 fps= 30 q=-0.0 size=     256KiB time=00:00:04.00 bitrate= 524.3kbits/s speed=2.05x",
+			Progress{},
+			Progress{FramesProcessed: 120, CurrentSize: 256, SizeUnit: "KiB", CurrentTime: 4, Bitrate: 524.3, BitrateUnit: "kbits/s", ProcessingRate: 2.05},
+			true,
+		},
+		{
+			"stderr duration and stream",
+			"  Duration: 00:01:30.50, start: 0.000000, bitrate: 1205 kb/s",
+			Progress{},
+			Progress{TotalDuration: 90.5},
+			true,
+		},
+		{
+			"stderr video stream",
+			"  Stream #0:0(und): Video: h264 (High), yuv420p, 1280x720 [SAR 1:1 DAR 16:9], 30 fps",
+			Progress{},
+			Progress{Width: 1280, Height: 720},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.start
+			changed := ParseProgressLine(tt.line, &got)
+			if got != tt.want {
+				t.Errorf("ParseProgressLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+			if changed != tt.changed {
+				t.Errorf("ParseProgressLine(%q) changed = %v, want %v", tt.line, changed, tt.changed)
+			}
+		})
+	}
+}
+
+// A -progress pipe:1 stream as FFmpeg 6 writes it for a 4 second clip,
+// cut down to three reports
+const capturedProgress = `frame=30
+fps=0.00
+stream_0_0_q=-0.0
+bitrate=N/A
+total_size=N/A
+out_time_us=1000000
+out_time_ms=1000000
+out_time=00:00:01.000000
+dup_frames=0
+drop_frames=0
+speed=N/A
+progress=continue
+frame=90
+fps=45.00
+stream_0_0_q=-0.0
+bitrate=N/A
+total_size=N/A
+out_time_us=3000000
+out_time_ms=3000000
+out_time=00:00:03.000000
+dup_frames=0
+drop_frames=0
+speed=1.5x
+progress=continue
+frame=120
+fps=44.12
+stream_0_0_q=-0.0
+bitrate= 700.2kbits/s
+total_size=350109
+out_time_us=4000000
+out_time_ms=4000000
+out_time=00:00:04.000000
+dup_frames=0
+drop_frames=0
+speed=1.47x
+progress=end
+`
+
+func TestParseProgressStream(t *testing.T) {
+	const duration = 4.0
+	var update Progress
+	var percents []float64
+	for _, line := range strings.Split(capturedProgress, "\n") {
+		ParseProgressLine(line, &update)
+		// Each report ends with a progress line
+		if strings.HasPrefix(line, "progress=") {
+			percents = append(percents, update.CurrentTime/duration*100)
+		}
+	}
+
+	want := []float64{25, 75, 100}
+	if len(percents) != len(want) {
+		t.Fatalf("got %d reports, want %d", len(percents), len(want))
+	}
+	for i := range want {
+		if math.Abs(percents[i]-want[i]) > 1e-9 {
+			t.Errorf("report %d: %.2f%%, want %.2f%%", i, percents[i], want[i])
+		}
+	}
+
+	final := Progress{CurrentTime: 4, ProcessingRate: 1.47, CurrentSize: 350109, SizeUnit: "b", Bitrate: 700.2, BitrateUnit: "kbits/s", FramesProcessed: 120, Done: true}
+	if update != final {
+		t.Errorf("final progress = %+v, want %+v", update, final)
+	}
+}
+
+func TestTimeToSeconds(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"01:02:03", 3723, false},
+		{"00:00:00", 0, false},
+		{"1:02:03.5", 3723.5, false},
+		{"02:03", 123, false},
+		{"2:03.25", 123.25, false},
+		{"90", 90, false},
+		{"7.5", 7.5, false},
+		{"0.04", 0.04, false},
+		{" 12 ", 12, false},
+		{"100:00:00", 360000, false},
+		{"1:2:3:4", 0, true},
+		{"abc", 0, true},
+		{"-5", 0, true},
+		{"1:60", 0, true},
+		{"1:60:00", 0, true},
+		{"1.5:30", 0, true},
+		{"1:", 0, true},
+		{":30", 0, true},
+		{"1e3", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := TimeToSeconds(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("TimeToSeconds(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TimeToSeconds(%q) = %g, want %g", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// pkg/gifmaker/ranges.go
+package gifmaker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TimeRange is a part of the source video, in seconds
+type TimeRange struct {
+	Start float64
+	End   float64
+}
+
+// ParseTimeRanges parses start-end pairs such as "0-2" or
+// "00:01:10-00:01:12.5". Times may be seconds, MM:SS or HH:MM:SS. The
+// ranges are returned in the order they appear in the video, and may not
+// overlap since each part of the video can only be shown once.
+func ParseTimeRanges(pairs []string) ([]TimeRange, error) {
+	var ranges []TimeRange
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		startStr, endStr, ok := strings.Cut(pair, "-")
+		if !ok || strings.TrimSpace(startStr) == "" || strings.TrimSpace(endStr) == "" {
+			return nil, fmt.Errorf("invalid range %q (expected start-end, e.g. 0-2 or 00:01:10-00:01:12)", pair)
+		}
+
+		start, err := TimeToSeconds(strings.TrimSpace(startStr))
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid start time in range %q", pair)
+		}
+		end, err := TimeToSeconds(strings.TrimSpace(endStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time in range %q", pair)
+		}
+		if end <= start {
+			return nil, fmt.Errorf("range %q ends before it starts", pair)
+		}
+
+		ranges = append(ranges, TimeRange{Start: start, End: end})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Start < ranges[i-1].End {
+			return nil, fmt.Errorf("ranges %s and %s overlap", ranges[i-1], ranges[i])
+		}
+	}
+	return ranges, nil
+}
+
+// String formats the range the way ParseTimeRanges reads it, in seconds
+func (r TimeRange) String() string {
+	return fmt.Sprintf("%s-%s", formatExprNumber(r.Start), formatExprNumber(r.End))
+}
+
+// RangesDuration returns how long the ranges play for back to back
+func RangesDuration(ranges []TimeRange) float64 {
+	var total float64
+	for _, r := range ranges {
+		total += r.End - r.Start
+	}
+	return total
+}
+
+// RangeSelectFilter returns a select filter that keeps only the frames in
+// the ranges, or "" if there are none. Each range includes its start but
+// not its end, so a frame on the boundary of two ranges isn't shown twice.
+func RangeSelectFilter(ranges []TimeRange) string {
+	if len(ranges) == 0 {
+		return ""
+	}
+	terms := make([]string, len(ranges))
+	for i, r := range ranges {
+		terms[i] = fmt.Sprintf("gte(t,%s)*lt(t,%s)", formatExprNumber(r.Start), formatExprNumber(r.End))
+	}
+	return fmt.Sprintf("select='%s'", strings.Join(terms, "+"))
+}
+
+// RangeTimestampFilter returns a setpts filter that closes the gaps
+// between the ranges, so the selected frames play back to back from zero,
+// or "" if there are no ranges. Timestamps are expected to be the
+// source's own, as RangeSelectFilter sees them.
+func RangeTimestampFilter(ranges []TimeRange) string {
+	if len(ranges) == 0 {
+		return ""
+	}
+	// Every range after the first moves back by the gap before it
+	expr := "T-" + formatExprNumber(ranges[0].Start)
+	for i := 1; i < len(ranges); i++ {
+		gap := ranges[i].Start - ranges[i-1].End
+		if gap > 0 {
+			expr += fmt.Sprintf("-gte(T,%s)*%s", formatExprNumber(ranges[i].Start), formatExprNumber(gap))
+		}
+	}
+	return fmt.Sprintf("setpts='(%s)/TB'", expr)
+}
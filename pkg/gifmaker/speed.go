@@ -0,0 +1,135 @@
+// pkg/gifmaker/speed.go
+package gifmaker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SpeedKeyframe sets the playback speed at a point of the clip. Between
+// keyframes the speed changes linearly.
+type SpeedKeyframe struct {
+	Time   float64 // Seconds from the start of the clip
+	Factor float64 // Playback speed, e.g. 2 for twice as fast
+}
+
+// ParseSpeedCurve parses comma-separated time:factor keyframes, such as
+// "0:1,3:4". Times may be seconds, MM:SS or HH:MM:SS, and must increase.
+// A lone factor such as "2" is a constant speed.
+func ParseSpeedCurve(s string) ([]SpeedKeyframe, error) {
+	points := strings.Split(s, ",")
+	if len(points) == 1 && !strings.Contains(s, ":") {
+		s = "0:" + strings.TrimSpace(s)
+		points = []string{s}
+	}
+
+	var curve []SpeedKeyframe
+	for _, point := range points {
+		point = strings.TrimSpace(point)
+		sep := strings.LastIndex(point, ":")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid speed keyframe %q (expected time:factor)", point)
+		}
+
+		t, err := TimeToSeconds(point[:sep])
+		if err != nil || t < 0 || point[:sep] == "" {
+			return nil, fmt.Errorf("invalid time in speed keyframe %q", point)
+		}
+		factor, err := strconv.ParseFloat(point[sep+1:], 64)
+		if err != nil || factor <= 0 || math.IsInf(factor, 0) {
+			return nil, fmt.Errorf("invalid speed in keyframe %q (expected a number greater than 0)", point)
+		}
+		if len(curve) > 0 && t <= curve[len(curve)-1].Time {
+			return nil, fmt.Errorf("speed keyframe times must increase (%q comes after %gs)", point, curve[len(curve)-1].Time)
+		}
+
+		curve = append(curve, SpeedKeyframe{Time: t, Factor: factor})
+	}
+	return curve, nil
+}
+
+// SpeedCurveFilter returns a setpts filter that plays the clip at the
+// speeds of the curve, or "" if the curve is empty. A single keyframe
+// plays the whole clip at that speed. Timestamps are expected to start at
+// zero at the beginning of the clip.
+func SpeedCurveFilter(curve []SpeedKeyframe) string {
+	switch len(curve) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("setpts=PTS/%s", formatExprNumber(curve[0].Factor))
+	}
+
+	// The output time of a source time is the integral of 1/speed, built
+	// up segment by segment. The speed is constant before the first and
+	// after the last keyframe.
+	first, last := curve[0], curve[len(curve)-1]
+	offsets := make([]float64, len(curve))
+	offsets[0] = first.Time / first.Factor
+	for i := 0; i < len(curve)-1; i++ {
+		offsets[i+1] = offsets[i] + segmentOutputTime(curve[i], curve[i+1], curve[i+1].Time)
+	}
+	expr := fmt.Sprintf("%s+(T-%s)/%s", formatExprNumber(offsets[len(curve)-1]), formatExprNumber(last.Time), formatExprNumber(last.Factor))
+
+	// Nest the segments from the last one outwards
+	for i := len(curve) - 2; i >= 0; i-- {
+		expr = fmt.Sprintf("if(lt(T,%s),%s+%s,%s)",
+			formatExprNumber(curve[i+1].Time), formatExprNumber(offsets[i]), segmentExpr(curve[i], curve[i+1]), expr)
+	}
+	expr = fmt.Sprintf("if(lt(T,%s),T/%s,%s)", formatExprNumber(first.Time), formatExprNumber(first.Factor), expr)
+
+	return fmt.Sprintf("setpts=PTS-STARTPTS,setpts='(%s)/TB'", expr)
+}
+
+// SpeedCurveDuration returns how long a clip of the given length plays
+// for at the speeds of the curve
+func SpeedCurveDuration(curve []SpeedKeyframe, duration float64) float64 {
+	if len(curve) == 0 || duration <= 0 {
+		return duration
+	}
+
+	first, last := curve[0], curve[len(curve)-1]
+	if duration <= first.Time {
+		return duration / first.Factor
+	}
+	total := first.Time / first.Factor
+	for i := 0; i < len(curve)-1; i++ {
+		if duration <= curve[i+1].Time {
+			return total + segmentOutputTime(curve[i], curve[i+1], duration)
+		}
+		total += segmentOutputTime(curve[i], curve[i+1], curve[i+1].Time)
+	}
+	return total + (duration-last.Time)/last.Factor
+}
+
+// segmentOutputTime returns how long the part of the segment from a to b
+// up to source time t plays for
+func segmentOutputTime(a, b SpeedKeyframe, t float64) float64 {
+	slope := (b.Factor - a.Factor) / (b.Time - a.Time)
+	if slope == 0 {
+		return (t - a.Time) / a.Factor
+	}
+	return math.Log((a.Factor+slope*(t-a.Time))/a.Factor) / slope
+}
+
+// segmentExpr is segmentOutputTime as an FFmpeg expression of T
+func segmentExpr(a, b SpeedKeyframe) string {
+	slope := (b.Factor - a.Factor) / (b.Time - a.Time)
+	if slope == 0 {
+		return fmt.Sprintf("(T-%s)/%s", formatExprNumber(a.Time), formatExprNumber(a.Factor))
+	}
+	return fmt.Sprintf("log((%s+%s*(T-%s))/%s)/%s",
+		formatExprNumber(a.Factor), formatExprNumber(slope), formatExprNumber(a.Time), formatExprNumber(a.Factor), formatExprNumber(slope))
+}
+
+// formatExprNumber formats a number for an FFmpeg expression. Negative
+// numbers are wrapped in parentheses so they can follow an operator.
+func formatExprNumber(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if f < 0 {
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+// pkg/gifmaker/waveform.go
+package gifmaker
+
+import "fmt"
+
+// Audio visualizations for Options.Waveform
+const (
+	WaveformWaves    = "waves"    // The waveform, drawn as a line centered on the band
+	WaveformSpectrum = "spectrum" // A frequency spectrum that scrolls from right to left
+)
+
+// Where the audio visualization sits on the frame
+const (
+	WaveformBottom = "bottom"
+	WaveformTop    = "top"
+	WaveformCenter = "center"
+)
+
+// DefaultWaveformHeight is the height of the visualization band in percent
+// of the frame height
+const DefaultWaveformHeight = 25
+
+// The visualization is drawn at this size and then stretched to the band,
+// so it doesn't depend on the size of the frames
+const waveformDrawSize = "640x160"
+
+// waveformGraph overlays the audio visualization on the frames that come
+// out of the video chain. The result ends on the overlay filter without an
+// output label, so more filters can be chained onto it.
+func (o Options) waveformGraph(videoChain string) string {
+	height := o.WaveformHeight
+	if height <= 0 {
+		height = DefaultWaveformHeight
+	}
+
+	var y string
+	switch o.WaveformPosition {
+	case WaveformTop:
+		y = "0"
+	case WaveformCenter:
+		y = "(main_h-overlay_h)/2"
+	default:
+		y = "main_h-overlay_h"
+	}
+
+	// scale2ref stretches the visualization to the frame width, and pass
+	// leaves the frames alone if the audio ends before the video
+	return fmt.Sprintf("[0:v]%s[frame];[0:a]%s[wave];[wave][frame]scale2ref=w=main_w:h=trunc(main_h*%d/100)[vis][base];[base][vis]overlay=x=0:y=%s:eof_action=pass",
+		videoChain, o.waveformFilter(), height, y)
+}
+
+// waveformFilter returns the filters that draw the audio at the output
+// frame rate
+func (o Options) waveformFilter() string {
+	rate := o.FPS
+	if rate <= 0 {
+		rate = 25
+	}
+
+	if o.Waveform == WaveformSpectrum {
+		return fmt.Sprintf("showspectrum=s=%s:slide=scroll:mode=combined:color=intensity:scale=log,fps=%d", waveformDrawSize, rate)
+	}
+
+	color := o.WaveformColor
+	if color == "" {
+		color = "white"
+	}
+	return fmt.Sprintf("showwaves=s=%s:mode=cline:rate=%d:colors=%s", waveformDrawSize, rate, EscapeFilterValue(color))
+}